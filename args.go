@@ -0,0 +1,46 @@
+package config
+
+import "strings"
+
+// BindArgs reads --key=value and --key value pairs from args into a layer
+// that takes precedence over every other source. A flag without a value is
+// set to "true". A following argument that does not start with "--", such
+// as -5, is taken as its value. Other arguments are ignored, and parsing
+// stops at "--".
+func (c *ConfigManager) BindArgs(args []string) error {
+	parsed := make(map[string]ConfigMap)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "--") || len(arg) == 2 {
+			continue
+		}
+		key, value, hasValue := strings.Cut(arg[2:], "=")
+		if key == "" {
+			continue
+		}
+		if !hasValue {
+			value = "true"
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "--") {
+				value = args[i+1]
+				i++
+			}
+		}
+		lower := strings.ToLower(key)
+		parsed[lower] = ConfigMap{Key: lower, Value: value}
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return ErrFrozen
+	}
+	previous := c.argConfig
+	c.argConfig = parsed
+	if err := c.collapseLocked(); err != nil {
+		c.argConfig = previous
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import "testing"
+
+func TestBindArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		key  string
+		want string
+	}{
+		{"equals", []string{"--port=9"}, "port", "9"},
+		{"separate value", []string{"--port", "9"}, "port", "9"},
+		{"negative value", []string{"--offset", "-5"}, "offset", "-5"},
+		{"flag without value", []string{"--verbose", "--port=9"}, "verbose", "true"},
+		{"trailing flag", []string{"--verbose"}, "verbose", "true"},
+		{"upper case key", []string{"--Port=9"}, "port", "9"},
+		{"stops at double dash", []string{"--", "--port=9"}, "port", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfigManager()
+			if err := c.BindArgs(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if got := c.GetString(tt.key); got != tt.want {
+				t.Errorf("GetString(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindArgsOutrankLaterLayers(t *testing.T) {
+	c := NewConfigManager()
+	if err := c.BindArgs([]string{"--port=9"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetDefault("port", 80); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("port"); got != 9 {
+		t.Errorf("after SetDefault, GetInt(port) = %d, want 9", got)
+	}
+	if err := c.Set("port", 10); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("port"); got != 9 {
+		t.Errorf("after Set, GetInt(port) = %d, want 9", got)
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"reflect"
+	"time"
+)
+
+// Clone returns an independent deep copy of the manager. The clone is never
+// frozen, so overrides can be applied to a copy of a frozen config.
+func (c *ConfigManager) Clone() *ConfigManager {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return &ConfigManager{
+		configName:       c.configName,
+		configPath:       c.configPath,
+		configFileUsed:   c.configFileUsed,
+		configType:       c.configType,
+		envPrefix:        c.envPrefix,
+		envPrefixes:      append([]string(nil), c.envPrefixes...),
+		envNames:         cloneMap(c.envNames),
+		envBindings:      cloneMap(c.envBindings),
+		bareEnv:          cloneConfig(c.bareEnv),
+		mapConfig:        cloneConfig(c.mapConfig),
+		defaultConfig:    cloneConfig(c.defaultConfig),
+		envConfig:        cloneConfig(c.envConfig),
+		combinedConfig:   cloneConfig(c.combinedConfig),
+		comments:         cloneMap(c.comments),
+		durationUnit:     c.durationUnit,
+		keyDurationUnits: cloneMap(c.keyDurationUnits),
+		envKeyReplacer:   c.envKeyReplacer,
+		intRounding:      c.intRounding,
+		thousandsCommas:  c.thousandsCommas,
+		floatParsing:     c.floatParsing,
+		sliceSeparator:   c.sliceSeparator,
+		jsonPrefix:       c.jsonPrefix,
+		jsonIndent:       c.jsonIndent,
+		yamlIndent:       c.yamlIndent,
+		explicitDefaults: c.explicitDefaults,
+		standardPaths:    c.standardPaths,
+		interpolation:    c.interpolation,
+		mergeOnRead:      c.mergeOnRead,
+		deepMergeMaps:    c.deepMergeMaps,
+		listMergeKeys:    cloneMap(c.listMergeKeys),
+		automaticEnv:     c.automaticEnv,
+		envTypeInference: c.envTypeInference,
+		envOnly:          c.envOnly,
+		caseCollision:    c.caseCollision,
+		collapsed:        c.collapsed,
+		history:          append([]ChangeRecord(nil), c.history...),
+		historySize:      c.historySize,
+		schema:           c.schema,
+		types:            cloneMap(c.types),
+		searchedPaths:    append([]string(nil), c.searchedPaths...),
+		secretsFile:      c.secretsFile,
+		secretConfig:     cloneConfig(c.secretConfig),
+		argConfig:        cloneConfig(c.argConfig),
+		bestEffort:       c.bestEffort,
+		trackUsage:       c.trackUsage,
+	}
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	clone := make(map[K]V, len(m))
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
+
+func cloneConfig(m map[string]ConfigMap) map[string]ConfigMap {
+	if m == nil {
+		return nil
+	}
+	clone := make(map[string]ConfigMap, len(m))
+	for k, v := range m {
+		clone[k] = ConfigMap{Key: v.Key, Value: deepCopy(v.Value)}
+	}
+	return clone
+}
+
+// deepCopy copies maps and slices recursively so that the copy shares no
+// mutable state with the original.
+func deepCopy(in any) any {
+	switch val := in.(type) {
+	case nil, string, bool, int, int64, float64, time.Duration, time.Time:
+		return val
+	case map[string]any:
+		m := make(map[string]any, len(val))
+		for k, v := range val {
+			m[k] = deepCopy(v)
+		}
+		return m
+	case []any:
+		s := make([]any, len(val))
+		for i, v := range val {
+			s[i] = deepCopy(v)
+		}
+		return s
+	}
+	rv := reflect.ValueOf(in)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.IsNil() {
+			return in
+		}
+		m := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(iter.Key(), deepCopyValue(rv.Type().Elem(), iter.Value()))
+		}
+		return m.Interface()
+	case reflect.Slice:
+		if rv.IsNil() {
+			return in
+		}
+		s := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			s.Index(i).Set(deepCopyValue(rv.Type().Elem(), rv.Index(i)))
+		}
+		return s.Interface()
+	default:
+		return in
+	}
+}
+
+func deepCopyValue(t reflect.Type, v reflect.Value) reflect.Value {
+	copied := deepCopy(v.Interface())
+	if copied == nil {
+		return reflect.Zero(t)
+	}
+	return reflect.ValueOf(copied)
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrCaseCollision = errors.New("config keys differ only by case")
+
+// SetWarnOnCaseCollision makes reads fail when the config contains keys that
+// differ only by case, such as Port and port, which would otherwise silently
+// overwrite each other.
+func (c *ConfigManager) SetWarnOnCaseCollision(enable bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.caseCollision = enable
+}
+
+func checkCaseCollisions(data map[string]any) error {
+	collisions := caseCollisions("", data)
+	if len(collisions) == 0 {
+		return nil
+	}
+	sort.Strings(collisions)
+	return fmt.Errorf("%w: %s", ErrCaseCollision, strings.Join(collisions, "; "))
+}
+
+func caseCollisions(prefix string, data map[string]any) []string {
+	seen := make(map[string][]string, len(data))
+	var collisions []string
+	for k, v := range data {
+		lower := strings.ToLower(k)
+		seen[lower] = append(seen[lower], joinKey(prefix, k))
+		if m, ok := toStringMap(v); ok {
+			collisions = append(collisions, caseCollisions(joinKey(prefix, k), m)...)
+		}
+	}
+	for _, keys := range seen {
+		if len(keys) > 1 {
+			sort.Strings(keys)
+			collisions = append(collisions, strings.Join(keys, ", "))
+		}
+	}
+	return collisions
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func (c *ConfigManager) SetKeyComment(key, comment string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.comments == nil {
+		c.comments = make(map[string]string)
+	}
+	c.comments[strings.ToLower(key)] = comment
+}
+
+func formatComment(comment string) string {
+	lines := strings.Split(strings.TrimRight(comment, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = "# " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (c *ConfigManager) yamlNode(config map[string]any) (*yaml.Node, error) {
+	var node yaml.Node
+	if err := node.Encode(config); err != nil {
+		return nil, err
+	}
+	if node.Kind != yaml.MappingNode {
+		return &node, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if comment, ok := c.comments[strings.ToLower(key.Value)]; ok {
+			key.HeadComment = formatComment(comment)
+		}
+	}
+	return &node, nil
+}
+
+// addTOMLComments inserts comments above the top-level keys and tables of
+// an encoded TOML document. BurntSushi/toml has no comment support, so the
+// encoder output is annotated line by line.
+func (c *ConfigManager) addTOMLComments(data []byte) []byte {
+	if len(c.comments) == 0 {
+		return data
+	}
+	var out bytes.Buffer
+	inTable := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		var key string
+		switch {
+		case strings.HasPrefix(line, "[[") && strings.HasSuffix(line, "]]"):
+			inTable = true
+			key = line[2 : len(line)-2]
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			inTable = true
+			key = line[1 : len(line)-1]
+		case !inTable:
+			key, _, _ = strings.Cut(line, " = ")
+		}
+		if unquoted, err := strconv.Unquote(key); err == nil {
+			key = unquoted
+		}
+		if comment, ok := c.comments[strings.ToLower(key)]; ok && key != "" {
+			out.WriteString(formatComment(comment))
+			out.WriteByte('\n')
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,14 @@
+package config
+
+import "context"
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, cm *ConfigManager) context.Context {
+	return context.WithValue(ctx, contextKey{}, cm)
+}
+
+func FromContext(ctx context.Context) (*ConfigManager, bool) {
+	cm, ok := ctx.Value(contextKey{}).(*ConfigManager)
+	return cm, ok && cm != nil
+}
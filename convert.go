@@ -0,0 +1,399 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxExactFloat is the largest magnitude at which every integer is exactly
+// representable as a float64.
+const maxExactFloat = 1 << 53
+
+func toStringMap(in any) (map[string]any, bool) {
+	switch val := in.(type) {
+	case map[string]any:
+		return val, true
+	case map[any]any:
+		m := make(map[string]any, len(val))
+		for k, v := range val {
+			m[fmt.Sprintf("%v", k)] = v
+		}
+		return m, true
+	case nil:
+		return nil, false
+	}
+	rv := reflect.ValueOf(in)
+	if rv.Kind() != reflect.Map {
+		return nil, false
+	}
+	m := make(map[string]any, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		m[fmt.Sprintf("%v", iter.Key().Interface())] = iter.Value().Interface()
+	}
+	return m, true
+}
+
+// normalizeMapKeys converts maps at any depth to map[string]any, turning
+// numeric or other non-string keys into their string form.
+func normalizeMapKeys(in any) any {
+	if m, ok := toStringMap(in); ok {
+		out := make(map[string]any, len(m))
+		for k, v := range m {
+			out[k] = normalizeMapKeys(v)
+		}
+		return out
+	}
+	if s, ok := in.([]any); ok {
+		out := make([]any, len(s))
+		for i, v := range s {
+			out[i] = normalizeMapKeys(v)
+		}
+		return out
+	}
+	return in
+}
+
+func toFloat(in any) (float64, error) {
+	switch val := in.(type) {
+	case int:
+		return float64(val), nil
+	case int8:
+		return float64(val), nil
+	case int16:
+		return float64(val), nil
+	case int32:
+		return float64(val), nil
+	case int64:
+		return float64(val), nil
+	case uint:
+		return float64(val), nil
+	case uint8:
+		return float64(val), nil
+	case uint16:
+		return float64(val), nil
+	case uint32:
+		return float64(val), nil
+	case uint64:
+		return float64(val), nil
+	case float32:
+		return float64(val), nil
+	case float64:
+		return val, nil
+	case json.Number:
+		return val.Float64()
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrWrongType, err)
+		}
+		return f, nil
+	case nil:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("%w: cannot convert %T to a number", ErrWrongType, in)
+	}
+}
+
+func toDuration(in any, unit time.Duration) (time.Duration, error) {
+	switch val := in.(type) {
+	case time.Duration:
+		return val, nil
+	case string:
+		// bare numbers, typically from env vars, use the duration unit
+		// just like numeric values from a config file
+		if i, err := strconv.ParseInt(strings.TrimSpace(val), 10, 64); err == nil {
+			return time.Duration(i) * unit, nil
+		}
+		if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return floatToDuration(f, unit), nil
+		}
+		return parseDuration(val)
+	case float32:
+		return floatToDuration(float64(val), unit), nil
+	case float64:
+		return floatToDuration(val, unit), nil
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return time.Duration(i) * unit, nil
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return 0, err
+		}
+		return floatToDuration(f, unit), nil
+	case nil:
+		return 0, nil
+	default:
+		i, err := toInt64(in, IntTruncate)
+		if err != nil {
+			return 0, fmt.Errorf("%w: cannot convert %T to a duration", ErrWrongType, in)
+		}
+		return time.Duration(i) * unit, nil
+	}
+}
+
+// toBool treats any nonzero number, including negative numbers and numeric
+// strings such as "2", as true. Strings naming NaN or an infinity are an
+// error. Other strings are true only when they equal "true", ignoring case.
+func toBool(in any) (bool, error) {
+	switch val := in.(type) {
+	case bool:
+		return val, nil
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return false, fmt.Errorf("%w: %q is not a finite number", ErrWrongType, val)
+			}
+			return f != 0, nil
+		}
+		return strings.ToLower(val) == "true", nil
+	case time.Duration:
+		return val > 0, nil
+	case nil:
+		return false, nil
+	default:
+		f, err := toFloat(in)
+		if err != nil {
+			return false, fmt.Errorf("%w: cannot convert %T to a bool", ErrWrongType, in)
+		}
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return false, fmt.Errorf("%w: %v is not a finite number", ErrWrongType, f)
+		}
+		return f != 0, nil
+	}
+}
+
+func toInt64(in any, rounding IntRounding) (int64, error) {
+	switch val := in.(type) {
+	case int:
+		return int64(val), nil
+	case int8:
+		return int64(val), nil
+	case int16:
+		return int64(val), nil
+	case int32:
+		return int64(val), nil
+	case int64:
+		return val, nil
+	case uint:
+		if uint64(val) > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d overflows int64", val)
+		}
+		return int64(val), nil
+	case uint8:
+		return int64(val), nil
+	case uint16:
+		return int64(val), nil
+	case uint32:
+		return int64(val), nil
+	case uint64:
+		if val > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d overflows int64", val)
+		}
+		return int64(val), nil
+	case float32:
+		return floatToInt64(float64(val), rounding)
+	case float64:
+		return floatToInt64(val, rounding)
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i, nil
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return 0, err
+		}
+		return floatToInt64(f, rounding)
+	case string:
+		s := stripDigitSeparators(strings.TrimSpace(val))
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err == nil {
+			return i, nil
+		}
+		// numeric strings with a decimal part, such as "5.0"
+		if f, ferr := strconv.ParseFloat(s, 64); ferr == nil {
+			return floatToInt64(f, rounding)
+		}
+		return 0, fmt.Errorf("%w: %w", ErrWrongType, err)
+	case nil:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("%w: cannot convert %T to an integer", ErrWrongType, in)
+	}
+}
+
+// stripDigitSeparators removes underscores placed between digits, as in
+// 1_000_000.
+func stripDigitSeparators(s string) string {
+	if !strings.Contains(s, "_") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '_' && i > 0 && i+1 < len(s) && isDigit(s[i-1]) && isDigit(s[i+1]) {
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func floatToInt64(f float64, rounding IntRounding) (int64, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("value %v is not a finite number", f)
+	}
+	if math.Abs(f) > maxExactFloat {
+		return 0, fmt.Errorf("value %v exceeds the exact integer range of float64", f)
+	}
+	switch rounding {
+	case IntRound:
+		f = math.Round(f)
+	case IntCeil:
+		f = math.Ceil(f)
+	}
+	return int64(f), nil
+}
+
+func toUint64(in any, rounding IntRounding) (uint64, error) {
+	switch val := in.(type) {
+	case uint:
+		return uint64(val), nil
+	case uint8:
+		return uint64(val), nil
+	case uint16:
+		return uint64(val), nil
+	case uint32:
+		return uint64(val), nil
+	case uint64:
+		return val, nil
+	case string:
+		if u, err := strconv.ParseUint(stripDigitSeparators(strings.TrimSpace(val)), 10, 64); err == nil {
+			return u, nil
+		}
+	case json.Number:
+		if u, err := strconv.ParseUint(val.String(), 10, 64); err == nil {
+			return u, nil
+		}
+	}
+	i, err := toInt64(in, rounding)
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 {
+		return 0, fmt.Errorf("value %d is negative", i)
+	}
+	return uint64(i), nil
+}
+
+// toSlice converts each element of a slice value with conv, skipping
+// elements that cannot be converted.
+func toSlice[T any](in any, conv func(any) (T, error)) []T {
+	rv := reflect.ValueOf(in)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+	ret := make([]T, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		v, err := conv(rv.Index(i).Interface())
+		if err != nil {
+			continue
+		}
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+func toInt(in any, rounding IntRounding) (int, error) {
+	i, err := toInt64(in, rounding)
+	if err != nil {
+		return 0, err
+	}
+	if i > math.MaxInt || i < math.MinInt {
+		return 0, fmt.Errorf("value %d overflows int", i)
+	}
+	return int(i), nil
+}
+
+// normalizeNumbers replaces json.Number values, which other encoders would
+// emit as strings, with int64 or float64. float32 values are widened to the
+// float64 with the same shortest decimal form, so 0.1 is written as 0.1 by
+// every encoder rather than 0.10000000149011612.
+func normalizeNumbers(in any) any {
+	switch val := in.(type) {
+	case float32:
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(float64(val), 'g', -1, 32), 64)
+		return f
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+		return val.String()
+	case map[string]any:
+		m := make(map[string]any, len(val))
+		for k, v := range val {
+			m[k] = normalizeNumbers(v)
+		}
+		return m
+	case []any:
+		s := make([]any, len(val))
+		for i, v := range val {
+			s[i] = normalizeNumbers(v)
+		}
+		return s
+	default:
+		return in
+	}
+}
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	"15:04:05.999999999",
+}
+
+// toTime converts config values to a time.Time. TOML local dates, times and
+// datetimes carry no zone and are interpreted as UTC, as are strings
+// without an offset.
+func toTime(in any) (time.Time, error) {
+	switch val := in.(type) {
+	case time.Time:
+		// BurntSushi/toml marks local values with these zone names
+		switch val.Location().String() {
+		case "date-local", "time-local", "datetime-local":
+			return time.Date(val.Year(), val.Month(), val.Day(), val.Hour(), val.Minute(), val.Second(), val.Nanosecond(), time.UTC), nil
+		}
+		return val, nil
+	case string:
+		s := strings.TrimSpace(val)
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("cannot parse %q as a time", val)
+	case nil:
+		return time.Time{}, nil
+	default:
+		i, err := toInt64(in, IntTruncate)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: cannot convert %T to a time", ErrWrongType, in)
+		}
+		return time.Unix(i, 0).UTC(), nil
+	}
+}
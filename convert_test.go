@@ -0,0 +1,73 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestJSONNumbers(t *testing.T) {
+	c := NewConfigManager()
+	if err := c.SetConfigType("json"); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"port": 8080, "ratio": 0.75, "big": 9007199254740993}`
+	if err := c.ReadConfig(strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("port").(json.Number); !ok {
+		t.Errorf("Get(port) = %T, want json.Number", c.Get("port"))
+	}
+	if got, err := c.GetIntE("port"); err != nil || got != 8080 {
+		t.Errorf("GetIntE(port) = %d, %v, want 8080", got, err)
+	}
+	if got, err := c.GetFloat64E("port"); err != nil || got != 8080 {
+		t.Errorf("GetFloat64E(port) = %v, %v, want 8080", got, err)
+	}
+	if got, err := c.GetFloat64E("ratio"); err != nil || got != 0.75 {
+		t.Errorf("GetFloat64E(ratio) = %v, %v, want 0.75", got, err)
+	}
+	if got := c.GetInt("ratio"); got != 0 {
+		t.Errorf("GetInt(ratio) = %d, want 0 with truncation", got)
+	}
+	if got := c.GetInt64("big"); got != 9007199254740993 {
+		t.Errorf("GetInt64(big) = %d, want 9007199254740993 without float rounding", got)
+	}
+}
+
+func TestToBool(t *testing.T) {
+	tests := []struct {
+		in      any
+		want    bool
+		wantErr bool
+	}{
+		{"2", true, false},
+		{" -0.5 ", true, false},
+		{"0", false, false},
+		{"TRUE", true, false},
+		{"yes", false, false},
+		{"nan", false, true},
+		{"inf", false, true},
+		{"-Inf", false, true},
+		{"+infinity", false, true},
+		{math.NaN(), false, true},
+		{math.Inf(1), false, true},
+		{3, true, false},
+		{nil, false, false},
+	}
+	for _, tt := range tests {
+		got, err := toBool(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("toBool(%#v) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err != nil && !errors.Is(err, ErrWrongType) {
+			t.Errorf("toBool(%#v) error = %v, want ErrWrongType", tt.in, err)
+		}
+		if got != tt.want {
+			t.Errorf("toBool(%#v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
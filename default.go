@@ -1,6 +1,12 @@
 package config
 
-import "time"
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
 
 var defaultConfigManager = NewConfigManager()
 
@@ -28,20 +34,24 @@ func SetEnvPrefix(prefix string) {
 	defaultConfigManager.SetEnvPrefix(prefix)
 }
 
+func SetEnvPrefixes(prefixes ...string) {
+	defaultConfigManager.SetEnvPrefixes(prefixes...)
+}
+
 func SetConfigType(configType string) error {
 	return defaultConfigManager.SetConfigType(configType)
 }
 
-func SetDefault(key string, value any) {
-	defaultConfigManager.SetDefault(key, value)
+func SetDefault(key string, value any) error {
+	return defaultConfigManager.SetDefault(key, value)
 }
 
-func Set(key string, value any) {
-	defaultConfigManager.Set(key, value)
+func Set(key string, value any) error {
+	return defaultConfigManager.Set(key, value)
 }
 
-func WriteConfig() {
-	defaultConfigManager.WriteConfig()
+func WriteConfig() error {
+	return defaultConfigManager.WriteConfig()
 }
 
 func ConfigFileUsed() string {
@@ -67,3 +77,351 @@ func GetStringMap(key string) map[string]any {
 func GetStringSlice(key string) []string {
 	return defaultConfigManager.GetStringSlice(key)
 }
+
+func SetSliceSeparator(sep string) {
+	defaultConfigManager.SetSliceSeparator(sep)
+}
+
+func Unmarshal(out any) error {
+	return defaultConfigManager.Unmarshal(out)
+}
+
+func UnmarshalExact(out any) error {
+	return defaultConfigManager.UnmarshalExact(out)
+}
+
+func UnmarshalKey(key string, out any) error {
+	return defaultConfigManager.UnmarshalKey(key, out)
+}
+
+func WriteConfigMinimal() error {
+	return defaultConfigManager.WriteConfigMinimal()
+}
+
+func DiffFile(path string) ([]ConfigDiff, error) {
+	return defaultConfigManager.DiffFile(path)
+}
+
+func ReadConfig(in io.Reader) error {
+	return defaultConfigManager.ReadConfig(in)
+}
+
+func ReadConfigStdin() error {
+	return defaultConfigManager.ReadConfigStdin()
+}
+
+func EnvPrefix() string {
+	return defaultConfigManager.EnvPrefix()
+}
+
+func SetKeyComment(key, comment string) {
+	defaultConfigManager.SetKeyComment(key, comment)
+}
+
+func SetDurationUnit(unit time.Duration) {
+	defaultConfigManager.SetDurationUnit(unit)
+}
+
+func GetEnum(key string, allowed []string) (string, error) {
+	return defaultConfigManager.GetEnum(key, allowed)
+}
+
+func GetPercent(key string) float64 {
+	return defaultConfigManager.GetPercent(key)
+}
+
+func GetPercentE(key string) (float64, error) {
+	return defaultConfigManager.GetPercentE(key)
+}
+
+func SetDefaultsFromStruct(s any) error {
+	return defaultConfigManager.SetDefaultsFromStruct(s)
+}
+
+func BindStruct(s any) error {
+	return defaultConfigManager.BindStruct(s)
+}
+
+func SetEnvKeyReplacer(r *strings.Replacer) {
+	defaultConfigManager.SetEnvKeyReplacer(r)
+}
+
+func Range(fn func(key string, value any) bool) {
+	defaultConfigManager.Range(fn)
+}
+
+func ReadRemoteConfig(ctx context.Context, url string, opts ...RemoteOption) error {
+	return defaultConfigManager.ReadRemoteConfig(ctx, url, opts...)
+}
+
+func AddStandardPaths() {
+	defaultConfigManager.AddStandardPaths()
+}
+
+func GetIntE(key string) (int, error) {
+	return defaultConfigManager.GetIntE(key)
+}
+
+func WriteConfigTo(w io.Writer, configType string) error {
+	return defaultConfigManager.WriteConfigTo(w, configType)
+}
+
+func EnableInterpolation(enable bool) {
+	defaultConfigManager.EnableInterpolation(enable)
+}
+
+func GetSlice(key string) []any {
+	return defaultConfigManager.GetSlice(key)
+}
+
+func IsSet(key string) bool {
+	return defaultConfigManager.IsSet(key)
+}
+
+func IsNull(key string) bool {
+	return defaultConfigManager.IsNull(key)
+}
+
+func SetMergeOnRead(enable bool) {
+	defaultConfigManager.SetMergeOnRead(enable)
+}
+
+func GetBoolE(key string) (bool, error) {
+	return defaultConfigManager.GetBoolE(key)
+}
+
+func GetDurationE(key string) (time.Duration, error) {
+	return defaultConfigManager.GetDurationE(key)
+}
+
+func GetInt64(key string) int64 {
+	return defaultConfigManager.GetInt64(key)
+}
+
+func GetInt64E(key string) (int64, error) {
+	return defaultConfigManager.GetInt64E(key)
+}
+
+func SetIntRounding(mode IntRounding) {
+	defaultConfigManager.SetIntRounding(mode)
+}
+
+func SetThousandsCommas(enable bool) {
+	defaultConfigManager.SetThousandsCommas(enable)
+}
+
+func SetJSONIndent(prefix, indent string) {
+	defaultConfigManager.SetJSONIndent(prefix, indent)
+}
+
+func SetYAMLIndent(n int) {
+	defaultConfigManager.SetYAMLIndent(n)
+}
+
+func RegisterSchema(schema any) {
+	defaultConfigManager.RegisterSchema(schema)
+}
+
+func RegisterType(key string, kind reflect.Kind) {
+	defaultConfigManager.RegisterType(key, kind)
+}
+
+func SearchedPaths() []string {
+	return defaultConfigManager.SearchedPaths()
+}
+
+func SetDeepMergeMaps(enable bool) {
+	defaultConfigManager.SetDeepMergeMaps(enable)
+}
+
+func AutomaticEnv() {
+	defaultConfigManager.AutomaticEnv()
+}
+
+func BindEnv(key string, envVars ...string) error {
+	return defaultConfigManager.BindEnv(key, envVars...)
+}
+
+func SetAutomaticEnv(enable bool) {
+	defaultConfigManager.SetAutomaticEnv(enable)
+}
+
+func SetKeyDurationUnit(key string, unit time.Duration) {
+	defaultConfigManager.SetKeyDurationUnit(key, unit)
+}
+
+func SetWarnOnCaseCollision(enable bool) {
+	defaultConfigManager.SetWarnOnCaseCollision(enable)
+}
+
+func Freeze() {
+	defaultConfigManager.Freeze()
+}
+
+func FlattenStringMap(key string) map[string]any {
+	return defaultConfigManager.FlattenStringMap(key)
+}
+
+func GetUint64(key string) uint64 {
+	return defaultConfigManager.GetUint64(key)
+}
+
+func GetUint64E(key string) (uint64, error) {
+	return defaultConfigManager.GetUint64E(key)
+}
+
+func GetInt64Slice(key string) []int64 {
+	return defaultConfigManager.GetInt64Slice(key)
+}
+
+func GetUint64Slice(key string) []uint64 {
+	return defaultConfigManager.GetUint64Slice(key)
+}
+
+func EnableHistory(size int) {
+	defaultConfigManager.EnableHistory(size)
+}
+
+func History() []ChangeRecord {
+	return defaultConfigManager.History()
+}
+
+func Clone() *ConfigManager {
+	return defaultConfigManager.Clone()
+}
+
+func ReadInConfigDir(dir string) error {
+	return defaultConfigManager.ReadInConfigDir(dir)
+}
+
+func GetBoolPtr(key string) *bool {
+	return defaultConfigManager.GetBoolPtr(key)
+}
+
+func GetStringMapString(key string) map[string]string {
+	return defaultConfigManager.GetStringMapString(key)
+}
+
+func GetTime(key string) time.Time {
+	return defaultConfigManager.GetTime(key)
+}
+
+func GetTimeE(key string) (time.Time, error) {
+	return defaultConfigManager.GetTimeE(key)
+}
+
+func EnvKeys() []string {
+	return defaultConfigManager.EnvKeys()
+}
+
+func EnvSettings() map[string]string {
+	return defaultConfigManager.EnvSettings()
+}
+
+func AllKeys() []string {
+	return defaultConfigManager.AllKeys()
+}
+
+func AllSettings() map[string]any {
+	return defaultConfigManager.AllSettings()
+}
+
+func AllSettingsOriginalCase() map[string]any {
+	return defaultConfigManager.AllSettingsOriginalCase()
+}
+
+func TrackUsage(enable bool) {
+	defaultConfigManager.TrackUsage(enable)
+}
+
+func UnusedKeys() []string {
+	return defaultConfigManager.UnusedKeys()
+}
+
+func SetSecretsFile(path string) {
+	defaultConfigManager.SetSecretsFile(path)
+}
+
+func WatchConfig(ctx context.Context, interval time.Duration, onChange func(error)) error {
+	return defaultConfigManager.WatchConfig(ctx, interval, onChange)
+}
+
+func SetBestEffortLoad(enable bool) {
+	defaultConfigManager.SetBestEffortLoad(enable)
+}
+
+func Warnings() <-chan error {
+	return defaultConfigManager.Warnings()
+}
+
+func LookupString(key string) (string, bool) {
+	return defaultConfigManager.LookupString(key)
+}
+
+func LookupInt(key string) (int, bool) {
+	return defaultConfigManager.LookupInt(key)
+}
+
+func LookupInt64(key string) (int64, bool) {
+	return defaultConfigManager.LookupInt64(key)
+}
+
+func LookupBool(key string) (bool, bool) {
+	return defaultConfigManager.LookupBool(key)
+}
+
+func LookupDuration(key string) (time.Duration, bool) {
+	return defaultConfigManager.LookupDuration(key)
+}
+
+func LookupStringSlice(key string) ([]string, bool) {
+	return defaultConfigManager.LookupStringSlice(key)
+}
+
+func SetEnvTypeInference(enable bool) {
+	defaultConfigManager.SetEnvTypeInference(enable)
+}
+
+func Reload() (bool, error) {
+	return defaultConfigManager.Reload()
+}
+
+func SetFloatParsing(opts FloatParsing) {
+	defaultConfigManager.SetFloatParsing(opts)
+}
+
+func GetFloat64(key string) float64 {
+	return defaultConfigManager.GetFloat64(key)
+}
+
+func GetFloat64E(key string) (float64, error) {
+	return defaultConfigManager.GetFloat64E(key)
+}
+
+func WithPrefix(prefix string) *ScopedConfig {
+	return defaultConfigManager.WithPrefix(prefix)
+}
+
+func Snapshot() ConfigView {
+	return defaultConfigManager.Snapshot()
+}
+
+func ValidateEnv() error {
+	return defaultConfigManager.ValidateEnv()
+}
+
+func Append(key string, values ...any) error {
+	return defaultConfigManager.Append(key, values...)
+}
+
+func SetListMergeKey(key, field string) {
+	defaultConfigManager.SetListMergeKey(key, field)
+}
+
+func BindArgs(args []string) error {
+	return defaultConfigManager.BindArgs(args)
+}
+
+func MarshalString() (string, error) {
+	return defaultConfigManager.MarshalString()
+}
@@ -0,0 +1,12 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPackageWriteConfigReturnsError(t *testing.T) {
+	if err := WriteConfig(); !errors.Is(err, ErrNoConfigFile) {
+		t.Errorf("WriteConfig() error = %v, want ErrNoConfigFile", err)
+	}
+}
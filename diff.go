@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	DiffAdded   DiffKind = "added"
+	DiffRemoved DiffKind = "removed"
+	DiffChanged DiffKind = "changed"
+)
+
+type (
+	DiffKind string
+
+	// ConfigDiff describes a single key whose value differs between two
+	// configs. Old is the value on disk and New is the in-memory value.
+	ConfigDiff struct {
+		Key  string
+		Kind DiffKind
+		Old  any
+		New  any
+	}
+)
+
+// DiffFile compares the file at path with the effective config. The file's
+// type is inferred from its extension, falling back to the config type.
+func (c *ConfigManager) DiffFile(path string) ([]ConfigDiff, error) {
+	configType, err := inferConfigType(path)
+	if err != nil {
+		c.mutex.RLock()
+		configType = c.configType
+		c.mutex.RUnlock()
+		if configType == "" {
+			return nil, fmt.Errorf("unable to determine config type from path %s: %w", path, ErrConfigTypeUnset)
+		}
+	}
+	fileData, err := readFile(path, configType)
+	if err != nil {
+		return nil, err
+	}
+	onDisk := make(map[string]ConfigMap, len(fileData))
+	for k, v := range fileData {
+		onDisk[strings.ToLower(k)] = ConfigMap{Key: k, Value: v}
+	}
+	c.ensureCollapsed()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return diffConfig(onDisk, c.combinedConfig), nil
+}
+
+func diffConfig(oldConfig, newConfig map[string]ConfigMap) []ConfigDiff {
+	var diffs []ConfigDiff
+	for k, n := range newConfig {
+		o, ok := oldConfig[k]
+		if !ok {
+			diffs = append(diffs, ConfigDiff{Key: n.Key, Kind: DiffAdded, New: n.Value})
+			continue
+		}
+		if !valuesEqual(o.Value, n.Value) {
+			diffs = append(diffs, ConfigDiff{Key: n.Key, Kind: DiffChanged, Old: o.Value, New: n.Value})
+		}
+	}
+	for k, o := range oldConfig {
+		if _, ok := newConfig[k]; !ok {
+			diffs = append(diffs, ConfigDiff{Key: o.Key, Kind: DiffRemoved, Old: o.Value})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return strings.ToLower(diffs[i].Key) < strings.ToLower(diffs[j].Key)
+	})
+	return diffs
+}
+
+// valuesEqual compares two config values structurally. Numbers are compared
+// by value regardless of their Go type, since the same setting may decode as
+// an int from one format and a float64 from another.
+func valuesEqual(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if isNumber(a) || isNumber(b) {
+		if !isNumber(a) || !isNumber(b) {
+			return false
+		}
+		fa, _ := toFloat(a)
+		fb, _ := toFloat(b)
+		return fa == fb
+	}
+	if ma, ok := toStringMap(a); ok {
+		mb, ok := toStringMap(b)
+		if !ok || len(ma) != len(mb) {
+			return false
+		}
+		for k, va := range ma {
+			vb, ok := mb[k]
+			if !ok || !valuesEqual(va, vb) {
+				return false
+			}
+		}
+		return true
+	}
+	ra, rb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if ra.Kind() == reflect.Slice || ra.Kind() == reflect.Array {
+		if rb.Kind() != reflect.Slice && rb.Kind() != reflect.Array {
+			return false
+		}
+		if ra.Len() != rb.Len() {
+			return false
+		}
+		for i := 0; i < ra.Len(); i++ {
+			if !valuesEqual(ra.Index(i).Interface(), rb.Index(i).Interface()) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func isNumber(v any) bool {
+	if _, ok := v.(string); ok {
+		return false
+	}
+	_, err := toFloat(v)
+	return err == nil
+}
@@ -0,0 +1,22 @@
+package config
+
+import "testing"
+
+func TestDiffFileInfersType(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "old.json", `{"port": 80, "host": "h"}`)
+	c := NewFromMap(map[string]any{"port": 8080, "name": "n"})
+	diffs, err := c.DiffFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := make(map[string]DiffKind, len(diffs))
+	for _, d := range diffs {
+		kinds[d.Key] = d.Kind
+	}
+	want := map[string]DiffKind{"port": DiffChanged, "name": DiffAdded, "host": DiffRemoved}
+	for k, kind := range want {
+		if kinds[k] != kind {
+			t.Errorf("diff for %s = %q, want %q", k, kinds[k], kind)
+		}
+	}
+}
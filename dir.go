@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadInConfigDir reads a directory in which each file is a key and its
+// trimmed content the value, as used by mounted Kubernetes ConfigMaps and
+// Secrets. Subdirectories become nested maps, and hidden entries such as
+// the ..data links created by Kubernetes are skipped.
+func (c *ConfigManager) ReadInConfigDir(dir string) error {
+	data, err := readDir(dir)
+	if err != nil {
+		return err
+	}
+	return c.loadConfig(data, nil)
+}
+
+func readDir(dir string) (map[string]any, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]any, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case info.IsDir():
+			sub, err := readDir(path)
+			if err != nil {
+				return nil, err
+			}
+			data[name] = sub
+		case info.Mode().IsRegular():
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			data[name] = strings.TrimSpace(string(content))
+		}
+	}
+	return data, nil
+}
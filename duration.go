@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (c *ConfigManager) SetDurationUnit(unit time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	c.durationUnit = unit
+}
+
+// SetKeyDurationUnit sets the unit for bare numeric durations at key,
+// overriding the unit set by SetDurationUnit.
+func (c *ConfigManager) SetKeyDurationUnit(key string, unit time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	if c.keyDurationUnits == nil {
+		c.keyDurationUnits = make(map[string]time.Duration)
+	}
+	c.keyDurationUnits[strings.ToLower(key)] = unit
+}
+
+func (c *ConfigManager) durationUnitFor(key string) time.Duration {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.keyUnit(strings.ToLower(key))
+}
+
+// keyUnit returns the unit for bare numeric durations at lower. The caller
+// must hold the lock.
+func (c *ConfigManager) keyUnit(lower string) time.Duration {
+	if unit, ok := c.keyDurationUnits[lower]; ok && unit > 0 {
+		return unit
+	}
+	if c.durationUnit <= 0 {
+		return time.Nanosecond
+	}
+	return c.durationUnit
+}
+
+func (c *ConfigManager) getDurationUnit() time.Duration {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	if c.durationUnit <= 0 {
+		return time.Nanosecond
+	}
+	return c.durationUnit
+}
+
+// floatToDuration interprets a bare number as a count of unit, rounding to
+// the nearest nanosecond.
+func floatToDuration(f float64, unit time.Duration) time.Duration {
+	return time.Duration(math.Round(f * float64(unit)))
+}
+
+// InfiniteDuration is returned for durations configured as "inf",
+// "infinite" or "never". "none" yields a zero duration.
+const InfiniteDuration = time.Duration(math.MaxInt64)
+
+func parseDuration(s string) (time.Duration, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "inf", "infinite", "never":
+		return InfiniteDuration, nil
+	case "none", "0":
+		return 0, nil
+	}
+	if strings.HasPrefix(s, "P") || strings.HasPrefix(s, "-P") {
+		return parseISODuration(s)
+	}
+	if strings.ContainsAny(s, "dw") {
+		return parseLongDuration(s)
+	}
+	return time.ParseDuration(s)
+}
+
+// parseLongDuration extends time.ParseDuration with d (24h) and w (7d)
+// units, as in 7d or 2w3d12h.
+func parseLongDuration(s string) (time.Duration, error) {
+	orig := s
+	neg := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	var (
+		d    time.Duration
+		rest strings.Builder
+	)
+	for s != "" {
+		i := 0
+		for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+			i++
+		}
+		j := i
+		for j < len(s) && !isDigit(s[j]) && s[j] != '.' {
+			j++
+		}
+		if i == 0 || j == i {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		var unit time.Duration
+		switch s[i:j] {
+		case "d":
+			unit = 24 * time.Hour
+		case "w":
+			unit = 7 * 24 * time.Hour
+		default:
+			rest.WriteString(s[:j])
+			s = s[j:]
+			continue
+		}
+		n, err := strconv.ParseFloat(s[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		d += time.Duration(n * float64(unit))
+		s = s[j:]
+	}
+	if rest.Len() > 0 {
+		r, err := time.ParseDuration(rest.String())
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", orig)
+		}
+		d += r
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// parseISODuration parses an ISO8601 duration such as PT30S or P1DT2H.
+// Years and months are rejected because their length is not fixed.
+func parseISODuration(s string) (time.Duration, error) {
+	orig := s
+	neg := false
+	if strings.HasPrefix(s, "-") {
+		neg = true
+		s = s[1:]
+	}
+	s = strings.TrimPrefix(s, "P")
+	if s == "" || s == "T" {
+		return 0, fmt.Errorf("invalid ISO8601 duration %q", orig)
+	}
+	var d time.Duration
+	inTime := false
+	for s != "" {
+		if s[0] == 'T' {
+			if inTime {
+				return 0, fmt.Errorf("invalid ISO8601 duration %q", orig)
+			}
+			inTime = true
+			s = s[1:]
+			continue
+		}
+		i := strings.IndexAny(s, "YMWDHS")
+		if i <= 0 {
+			return 0, fmt.Errorf("invalid ISO8601 duration %q", orig)
+		}
+		n, err := strconv.ParseFloat(strings.Replace(s[:i], ",", ".", 1), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO8601 duration %q", orig)
+		}
+		var unit time.Duration
+		switch designator := s[i]; {
+		case designator == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case designator == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case designator == 'H' && inTime:
+			unit = time.Hour
+		case designator == 'M' && inTime:
+			unit = time.Minute
+		case designator == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, fmt.Errorf("unsupported ISO8601 duration %q", orig)
+		}
+		d += time.Duration(n * float64(unit))
+		s = s[i+1:]
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFractionalDurations(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		unit time.Duration
+		want time.Duration
+	}{
+		{"fractional seconds", "timeout: 5.5\n", time.Second, 5500 * time.Millisecond},
+		{"sub-nanosecond rounds", "timeout: 0.0000000015\n", time.Second, 2 * time.Nanosecond},
+		{"fractional minutes", "timeout: 0.25\n", time.Minute, 15 * time.Second},
+		{"negative fraction", "timeout: -1.5\n", time.Second, -1500 * time.Millisecond},
+		{"fractional string", "timeout: \"2.5\"\n", time.Second, 2500 * time.Millisecond},
+		{"default unit", "timeout: 5.5\n", 0, 6 * time.Nanosecond},
+		{"whole number", "timeout: 30\n", time.Second, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfigManager()
+			if tt.unit != 0 {
+				c.SetDurationUnit(tt.unit)
+			}
+			if err := c.SetConfigType("yaml"); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.ReadConfig(strings.NewReader(tt.data)); err != nil {
+				t.Fatal(err)
+			}
+			if got := c.GetDuration("timeout"); got != tt.want {
+				t.Errorf("GetDuration(timeout) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
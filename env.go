@@ -0,0 +1,203 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvKeys returns the sorted names of the environment variables captured
+// under the current env prefix.
+func (c *ConfigManager) EnvKeys() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	keys := make([]string, 0, len(c.envConfig))
+	for k := range c.envConfig {
+		keys = append(keys, c.envNames[k])
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EnvSettings returns the captured environment variables keyed by their full
+// names. Values of variables that override keys from the secrets file are
+// redacted.
+func (c *ConfigManager) EnvSettings() map[string]string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	secrets := c.secretEnvKeys()
+	settings := make(map[string]string, len(c.envConfig))
+	for k, v := range c.envConfig {
+		if secrets[k] {
+			settings[c.envNames[k]] = redacted
+			continue
+		}
+		settings[c.envNames[k]] = fmt.Sprintf("%v", v.Value)
+	}
+	return settings
+}
+
+// SetEnvTypeInference makes env values that look like bools, integers,
+// floats or comma-separated lists resolve to those types instead of strings,
+// so they behave like values decoded from a config file.
+func (c *ConfigManager) SetEnvTypeInference(enable bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	previous := c.envTypeInference
+	c.envTypeInference = enable
+	if c.collapseLocked() != nil {
+		c.envTypeInference = previous
+	}
+}
+
+func inferType(in any) any {
+	s, ok := in.(string)
+	if !ok {
+		return in
+	}
+	if strings.Contains(s, ",") {
+		parts := strings.Split(s, ",")
+		list := make([]any, len(parts))
+		for i, p := range parts {
+			list[i] = inferScalar(strings.TrimSpace(p))
+		}
+		return list
+	}
+	return inferScalar(s)
+}
+
+func inferScalar(s string) any {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if i >= math.MinInt && i <= math.MaxInt {
+			return int(i)
+		}
+		return i
+	}
+	// ParseFloat accepts words such as "inf" and "nan", which are more
+	// likely meant as strings
+	if strings.ContainsAny(s, "0123456789") {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	switch strings.ToLower(s) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return s
+}
+
+// ValidateEnv converts the env value of every key declared with
+// RegisterType, including keys bound with BindEnv, and returns the failures
+// joined into one error, so bad env input can be reported at startup rather
+// than when a key is first read.
+func (c *ConfigManager) ValidateEnv() error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	keys := make([]string, 0, len(c.types))
+	for k := range c.types {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var errs []error
+	for _, k := range keys {
+		v, envVar, ok := c.boundEnv(k)
+		if !ok {
+			name := c.envKey(k)
+			if v, ok = c.envValue(name); !ok {
+				continue
+			}
+			envVar = c.envName(name)
+		}
+		if _, err := coerce(v.Value, c.types[k], c.intRounding); err != nil {
+			errs = append(errs, fmt.Errorf("env %s for key %s: %w", envVar, k, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// envName returns the name of the env var captured for lower. The caller
+// must hold the lock.
+func (c *ConfigManager) envName(lower string) string {
+	if name, ok := c.envNames[lower]; ok {
+		return name
+	}
+	return c.bareEnv[lower].Key
+}
+
+type envBinding struct {
+	key   string
+	names []string
+}
+
+// BindEnv resolves key from the named env vars, the first one set winning,
+// instead of the names derived from the key. Without names, key is bound to
+// the env prefix and key joined by an underscore and uppercased, after the
+// env key replacer is applied. Bound variables are read each time key is
+// looked up, even with automatic env disabled. They take precedence over
+// defaults and automatic env matches, while Set, secrets and command-line
+// args still override them. As with automatic env, a bound dotted key
+// overrides its leaf in a map from the config file, but a top-level key in
+// the file overrides the binding.
+func (c *ConfigManager) BindEnv(key string, envVars ...string) error {
+	if key == "" {
+		return fmt.Errorf("BindEnv: key is empty")
+	}
+	lower := strings.ToLower(key)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return ErrFrozen
+	}
+	if len(envVars) == 0 {
+		name := c.envKey(lower)
+		if c.envPrefix != "" {
+			name = strings.TrimSuffix(c.envPrefix, "_") + "_" + name
+		}
+		envVars = []string{strings.ToUpper(name)}
+	}
+	if c.envBindings == nil {
+		c.envBindings = make(map[string]envBinding)
+	}
+	previous, wasBound := c.envBindings[lower]
+	c.envBindings[lower] = envBinding{key: key, names: append([]string(nil), envVars...)}
+	if err := c.collapseLocked(); err != nil {
+		if wasBound {
+			c.envBindings[lower] = previous
+		} else {
+			delete(c.envBindings, lower)
+		}
+		return err
+	}
+	return nil
+}
+
+// boundEnv returns the value of the first set env var bound to lower, along
+// with the var's name. The caller must hold the lock.
+func (c *ConfigManager) boundEnv(lower string) (ConfigMap, string, bool) {
+	b, ok := c.envBindings[lower]
+	if !ok {
+		return ConfigMap{}, "", false
+	}
+	for _, name := range b.names {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		v := ConfigMap{Key: b.key, Value: value}
+		if c.envTypeInference {
+			v.Value = inferType(v.Value)
+		}
+		return v, name, true
+	}
+	return ConfigMap{}, "", false
+}
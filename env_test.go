@@ -0,0 +1,155 @@
+package config
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestBindEnv(t *testing.T) {
+	t.Setenv("MY_CUSTOM_PORT", "7")
+
+	c := NewConfigManager()
+	c.SetAutomaticEnv(false)
+	if err := c.BindEnv("port", "MY_CUSTOM_PORT"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("port"); got != 7 {
+		t.Errorf("bound var present: GetInt(port) = %d, want 7", got)
+	}
+	if err := c.SetDefault("port", 80); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Get("port"); got != 7 {
+		t.Errorf("after SetDefault: Get(port) = %#v, want int 7", got)
+	}
+	if err := c.Set("port", 9); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("port"); got != 9 {
+		t.Errorf("overridden by Set: GetInt(port) = %d, want 9", got)
+	}
+}
+
+func TestBindEnvAbsent(t *testing.T) {
+	os.Unsetenv("JETY_TEST_UNSET_VAR")
+
+	c := NewConfigManager()
+	if err := c.BindEnv("missing", "JETY_TEST_UNSET_VAR"); err != nil {
+		t.Fatal(err)
+	}
+	if c.IsSet("missing") {
+		t.Error("IsSet(missing) = true for an unset bound var")
+	}
+	if err := c.SetDefault("fallback", "default"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.BindEnv("fallback", "JETY_TEST_UNSET_VAR"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("fallback"); got != "default" {
+		t.Errorf("GetString(fallback) = %q, want default", got)
+	}
+}
+
+func TestBindEnvReadAtLookup(t *testing.T) {
+	c := NewConfigManager()
+	if err := c.BindEnv("database.url", "DATABASE_CONNECTION_STRING"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("database.url"); got != "" {
+		t.Errorf("before Setenv: GetString(database.url) = %q, want empty", got)
+	}
+	t.Setenv("DATABASE_CONNECTION_STRING", "postgres://db")
+	if got := c.GetString("database.url"); got != "postgres://db" {
+		t.Errorf("after Setenv: GetString(database.url) = %q, want postgres://db", got)
+	}
+}
+
+func TestBindEnvDefaultName(t *testing.T) {
+	t.Setenv("APP_DATABASE_HOST", "bound")
+
+	c := NewConfigManager()
+	c.SetEnvPrefix("APP")
+	c.SetAutomaticEnv(false)
+	if err := c.BindEnv("database_host"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("database_host"); got != "bound" {
+		t.Errorf("GetString(database_host) = %q, want bound", got)
+	}
+}
+
+func TestValidateEnvBound(t *testing.T) {
+	c := NewConfigManager()
+	c.RegisterType("retries", reflect.Int)
+	if err := c.BindEnv("retries", "JETY_TEST_RETRIES"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JETY_TEST_RETRIES", "many")
+	if err := c.ValidateEnv(); err == nil {
+		t.Error("ValidateEnv() = nil, want an error for JETY_TEST_RETRIES")
+	}
+}
+
+func TestEnvPrefix(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		automatic bool
+		key       string
+		want      string
+	}{
+		{"prefixed hit", map[string]string{"APP_PORT": "1"}, true, "port", "1"},
+		{"prefix case-insensitive", map[string]string{"app_Port": "2"}, true, "port", "2"},
+		{"prefix wins over bare", map[string]string{"APP_HOST": "prefixed", "HOST": "bare"}, true, "host", "prefixed"},
+		{"bare fallback", map[string]string{"JETY_TEST_BARE": "bare"}, true, "jety_test_bare", "bare"},
+		{"prefix without separator misses", map[string]string{"APPJETY_TEST_KEY": "x"}, true, "jety_test_key", ""},
+		{"other prefix misses", map[string]string{"OTHER_JETY_TEST_KEY": "x"}, true, "jety_test_key", ""},
+		{"automatic env off, prefixed", map[string]string{"APP_PORT": "1"}, false, "port", ""},
+		{"automatic env off, bare", map[string]string{"JETY_TEST_BARE": "bare"}, false, "jety_test_bare", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			c := NewConfigManager()
+			c.SetEnvPrefix("APP")
+			c.SetAutomaticEnv(tt.automatic)
+			if got := c.GetString(tt.key); got != tt.want {
+				t.Errorf("GetString(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutomaticEnvOffKeepsDefaults(t *testing.T) {
+	t.Setenv("APP_PORT", "1")
+
+	c := NewConfigManager()
+	c.SetEnvPrefix("APP")
+	c.SetAutomaticEnv(false)
+	if err := c.SetDefault("port", 80); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetInt("port"); got != 80 {
+		t.Errorf("GetInt(port) = %d, want 80", got)
+	}
+	c.SetAutomaticEnv(true)
+	if got := c.GetInt("port"); got != 1 {
+		t.Errorf("after SetAutomaticEnv(true), GetInt(port) = %d, want 1", got)
+	}
+}
+
+func TestEnvOnlyOverDottedDefault(t *testing.T) {
+	t.Setenv("SVC_DATABASE_HOST", "from-env")
+
+	c := NewEnvOnly("SVC")
+	if err := c.SetDefault("database.host", "localhost"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("database.host"); got != "from-env" {
+		t.Errorf("GetString(database.host) = %q, want from-env", got)
+	}
+}
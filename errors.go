@@ -0,0 +1,42 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// LoadError reports a config that could not be decoded. Path is empty when
+// the config was read from an io.Reader, and Key is only set when the
+// decoder can name the offending key.
+type LoadError struct {
+	Path string
+	Type string
+	Key  string
+	Err  error
+}
+
+func (e *LoadError) Error() string {
+	msg := "loading " + e.Type + " config"
+	if e.Path != "" {
+		msg += " " + e.Path
+	}
+	if e.Key != "" {
+		msg += fmt.Sprintf(" (near key %q)", e.Key)
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+func newLoadError(path string, ct configType, err error) error {
+	le := &LoadError{Path: path, Type: string(ct), Err: err}
+	var parseErr toml.ParseError
+	if errors.As(err, &parseErr) {
+		le.Key = parseErr.LastKey
+	}
+	return le
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FloatParsing enables richer interpretation of string values in
+// GetFloat64. With both options off, strings are parsed as plain floats.
+type FloatParsing struct {
+	// Percent divides values with a trailing % by 100, so "50%" is 0.5.
+	Percent bool
+	// Units scales values with a trailing decimal (k, M, G, T) or binary
+	// (Ki, Mi, Gi, Ti) multiplier, optionally followed by B, so "1.5k" is
+	// 1500 and "2MiB" is 2097152.
+	Units bool
+}
+
+var unitMultipliers = []struct {
+	suffix string
+	factor float64
+}{
+	// binary suffixes first so Ki is not read as a bare K
+	{"Ki", 1 << 10},
+	{"Mi", 1 << 20},
+	{"Gi", 1 << 30},
+	{"Ti", 1 << 40},
+	{"k", 1e3},
+	{"K", 1e3},
+	{"M", 1e6},
+	{"G", 1e9},
+	{"T", 1e12},
+}
+
+func (c *ConfigManager) SetFloatParsing(opts FloatParsing) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	c.floatParsing = opts
+}
+
+func (c *ConfigManager) GetFloat64(key string) float64 {
+	f, err := c.GetFloat64E(key)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func (c *ConfigManager) GetFloat64E(key string) (float64, error) {
+	v, ok := c.lookup(key)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	s, ok := v.Value.(string)
+	if !ok {
+		f, err := toFloat(v.Value)
+		if err != nil {
+			return 0, fmt.Errorf("key %s: %w", key, err)
+		}
+		return f, nil
+	}
+	c.mutex.RLock()
+	opts := c.floatParsing
+	c.mutex.RUnlock()
+	f, err := parseFloat(s, opts)
+	if err != nil {
+		return 0, fmt.Errorf("key %s: %w", key, err)
+	}
+	return f, nil
+}
+
+func parseFloat(s string, opts FloatParsing) (float64, error) {
+	s = strings.TrimSpace(s)
+	factor := 1.0
+	switch {
+	case opts.Percent && strings.HasSuffix(s, "%"):
+		s = strings.TrimSpace(strings.TrimSuffix(s, "%"))
+		factor = 0.01
+	case opts.Units:
+		trimmed := strings.TrimSuffix(s, "B")
+		for _, u := range unitMultipliers {
+			if strings.HasSuffix(trimmed, u.suffix) {
+				s = strings.TrimSpace(strings.TrimSuffix(trimmed, u.suffix))
+				factor = u.factor
+				break
+			}
+		}
+		if factor == 1 {
+			s = strings.TrimSpace(trimmed)
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrWrongType, err)
+	}
+	return f * factor, nil
+}
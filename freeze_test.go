@@ -0,0 +1,88 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFreezeIgnoresEnvSettings(t *testing.T) {
+	t.Setenv("ZZ_HOST", "env")
+	t.Setenv("ZZ_DB_HOST", "env")
+	t.Setenv("ZZ_COUNT", "3")
+
+	c := NewConfigManager()
+	if err := c.SetDefault("host", "default"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetDefault("db", map[string]any{"host": "default"}); err != nil {
+		t.Fatal(err)
+	}
+	c.SetAutomaticEnv(false)
+	c.Freeze()
+
+	c.SetEnvPrefix("ZZ")
+	c.SetEnvPrefixes("ZZ")
+	c.SetAutomaticEnv(true)
+	c.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	c.SetEnvTypeInference(true)
+	c.SetDeepMergeMaps(true)
+
+	for _, key := range []string{"host", "db.host"} {
+		if got := c.GetString(key); got != "default" {
+			t.Errorf("GetString(%q) = %q, want default", key, got)
+		}
+	}
+	if c.IsSet("count") {
+		t.Error("IsSet(count) = true after env settings on a frozen config")
+	}
+	if err := c.Set("host", "set"); !errors.Is(err, ErrFrozen) {
+		t.Errorf("Set() error = %v, want ErrFrozen", err)
+	}
+}
+
+func TestFreezeIgnoresParsingSettings(t *testing.T) {
+	c := NewConfigManager()
+	if err := c.SetConfigType("yaml"); err != nil {
+		t.Fatal(err)
+	}
+	data := "timeout: 5\nratio: 50%\ncount: 1,000\nitems: a;b\nport: \"80\"\nurl: ${host}\nhost: h\n"
+	if err := c.ReadConfig(strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	c.Freeze()
+
+	c.SetDurationUnit(time.Second)
+	c.SetKeyDurationUnit("timeout", time.Minute)
+	c.SetIntRounding(IntCeil)
+	c.SetThousandsCommas(true)
+	c.SetSliceSeparator(";")
+	c.SetFloatParsing(FloatParsing{Percent: true})
+	c.SetListMergeKey("items", "name")
+	c.RegisterType("port", reflect.Int)
+	c.EnableInterpolation(true)
+
+	if got := c.GetDuration("timeout"); got != 5 {
+		t.Errorf("GetDuration(timeout) = %v, want 5ns", got)
+	}
+	if _, err := c.GetFloat64E("ratio"); err == nil {
+		t.Error("GetFloat64E(ratio) = nil error, want percent parsing to stay off")
+	}
+	if got := c.GetInt("count"); got != 0 {
+		t.Errorf("GetInt(count) = %d, want 0 with thousands commas off", got)
+	}
+	if got := c.GetStringSlice("items"); len(got) != 1 {
+		t.Errorf("GetStringSlice(items) = %v, want one element", got)
+	}
+	if got := c.Get("port"); got != "80" {
+		t.Errorf("Get(port) = %#v, want the string 80", got)
+	}
+	if err := c.ReadConfig(strings.NewReader(data)); !errors.Is(err, ErrFrozen) {
+		t.Errorf("ReadConfig() error = %v, want ErrFrozen", err)
+	}
+	if got := c.GetString("url"); got != "${host}" {
+		t.Errorf("GetString(url) = %q, want it uninterpolated", got)
+	}
+}
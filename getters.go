@@ -2,108 +2,178 @@ package config
 
 import (
 	"fmt"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
-func (c *ConfigManager) Get(key string) any {
+func (c *ConfigManager) lookup(key string) (ConfigMap, bool) {
+	c.ensureCollapsed()
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
-	v, ok := c.combinedConfig[strings.ToLower(key)]
-	if !ok {
-		v, ok = c.envConfig[strings.ToLower(c.envPrefix+key)]
-		if !ok {
-			return nil
+	if c.trackUsage {
+		c.markUsed(strings.ToLower(key))
+	}
+	return c.find(key)
+}
+
+// find resolves key against the effective config. The caller must hold the
+// lock.
+func (c *ConfigManager) find(key string) (ConfigMap, bool) {
+	lower := strings.ToLower(key)
+	if _, ok := c.envBindings[lower]; ok {
+		// bound env vars are read at lookup time, so the collapsed value
+		// may be stale
+		if v, ok := c.resolve(lower); ok {
+			if kind, ok := c.types[lower]; ok && v.Value != nil {
+				if val, err := coerce(v.Value, kind, c.intRounding); err == nil {
+					v.Value = val
+				}
+			}
+			return v, true
 		}
+	} else if v, ok := c.combinedConfig[lower]; ok {
+		return v, true
 	}
-	return v.Value
+	if v, ok := c.envValue(c.envKey(lower)); ok {
+		return v, true
+	}
+	if v, ok := c.lookupNested(lower); ok {
+		return v, true
+	}
+	if strings.Contains(lower, ".") {
+		if v, ok := c.envValue(strings.ReplaceAll(lower, ".", "_")); ok {
+			return v, true
+		}
+	}
+	return ConfigMap{}, false
 }
 
-func (c *ConfigManager) GetBool(key string) bool {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-	v, ok := c.combinedConfig[strings.ToLower(key)]
-	if !ok {
-		v, ok = c.envConfig[strings.ToLower(c.envPrefix+key)]
+// lookupNested walks dotted keys such as database.host into the nested maps
+// of combinedConfig, at any depth. Keys that themselves contain dots, as set
+// with Set("a.b", ...), are tried longest first as the root of the walk.
+func (c *ConfigManager) lookupNested(lower string) (ConfigMap, bool) {
+	parts := strings.Split(lower, ".")
+	for i := len(parts) - 1; i > 0; i-- {
+		v, ok := c.combinedConfig[strings.Join(parts[:i], ".")]
 		if !ok {
-			return false
+			continue
+		}
+		if val, ok := walkNested(v.Value, parts[i:]); ok {
+			return ConfigMap{Key: lower, Value: val}, true
 		}
 	}
-	val := v.Value
-	switch val := val.(type) {
-	case bool:
-		return val
-	case string:
-		if strings.ToLower(val) == "true" {
-			return true
+	return ConfigMap{}, false
+}
+
+func walkNested(val any, parts []string) (any, bool) {
+	for _, part := range parts {
+		m, ok := toStringMap(val)
+		if !ok {
+			return nil, false
 		}
-		return false
-	case int:
-		if val == 0 {
-			return false
+		found := false
+		for k, mv := range m {
+			if strings.ToLower(k) == part {
+				val, found = mv, true
+				break
+			}
 		}
-		return true
-	case float32, float64:
-		if val == 0 {
-			return false
+		if !found {
+			return nil, false
 		}
-		return true
-	case nil:
+	}
+	return val, true
+}
+
+func (c *ConfigManager) Get(key string) any {
+	v, ok := c.lookup(key)
+	if !ok {
+		return nil
+	}
+	return v.Value
+}
+
+func (c *ConfigManager) GetBool(key string) bool {
+	b, err := c.GetBoolE(key)
+	if err != nil {
 		return false
-	case time.Duration:
-		if val == 0 || val < 0 {
-			return false
-		}
-		return true
-	default:
-		return val.(bool)
 	}
+	return b
 }
 
-func (c *ConfigManager) GetDuration(key string) time.Duration {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-	v, ok := c.combinedConfig[strings.ToLower(key)]
+func (c *ConfigManager) GetBoolE(key string) (bool, error) {
+	v, ok := c.lookup(key)
 	if !ok {
-		v, ok = c.envConfig[strings.ToLower(c.envPrefix+key)]
-		if !ok {
-			return 0
-		}
+		return false, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
 	}
-	val := v.Value
-	switch val := val.(type) {
-	case time.Duration:
-		return val
-	case string:
-		d, err := time.ParseDuration(val)
-		if err != nil {
-			return 0
-		}
-		return d
-	case int:
-		return time.Duration(val)
-	case float32:
-		return time.Duration(val)
-	case float64:
-		return time.Duration(val)
-	case nil:
+	b, err := toBool(v.Value)
+	if err != nil {
+		return false, fmt.Errorf("key %s: %w", key, err)
+	}
+	return b, nil
+}
+
+// GetBoolPtr returns nil when key is not set, distinguishing an absent flag
+// from one explicitly set to false.
+func (c *ConfigManager) GetBoolPtr(key string) *bool {
+	v, ok := c.lookup(key)
+	if !ok || v.Value == nil {
+		return nil
+	}
+	b, err := toBool(v.Value)
+	if err != nil {
+		return nil
+	}
+	return &b
+}
+
+func (c *ConfigManager) GetDuration(key string) time.Duration {
+	d, err := c.GetDurationE(key)
+	if err != nil {
 		return 0
-	default:
-		return val.(time.Duration)
+	}
+	return d
+}
+
+func (c *ConfigManager) GetDurationE(key string) (time.Duration, error) {
+	v, ok := c.lookup(key)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	d, err := toDuration(v.Value, c.durationUnitFor(key))
+	if err != nil {
+		return 0, fmt.Errorf("key %s: %w", key, err)
+	}
+	return d, nil
+}
 
+func (c *ConfigManager) GetTime(key string) time.Time {
+	t, err := c.GetTimeE(key)
+	if err != nil {
+		return time.Time{}
 	}
+	return t
+}
+
+func (c *ConfigManager) GetTimeE(key string) (time.Time, error) {
+	v, ok := c.lookup(key)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	t, err := toTime(v.Value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("key %s: %w", key, err)
+	}
+	return t, nil
 }
 
 func (c *ConfigManager) GetString(key string) string {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-	v, ok := c.combinedConfig[strings.ToLower(key)]
+	v, ok := c.lookup(key)
 	if !ok {
-		v, ok = c.envConfig[strings.ToLower(c.envPrefix+key)]
-		if !ok {
-			return ""
-		}
+		return ""
 	}
 
 	switch val := v.Value.(type) {
@@ -114,35 +184,98 @@ func (c *ConfigManager) GetString(key string) string {
 	}
 }
 
+// GetStringMap returns the map at key, including entries from environment
+// variables. Env vars are matched to map entries as follows, using MYAPP_ as
+// the env prefix and db as key:
+//
+//   - an env var named after the dotted path of an existing entry, with the
+//     env key replacer applied or with dots replaced by underscores,
+//     overrides that entry: MYAPP_DB_TLS_CERT overrides db.tls.cert;
+//   - any other env var named MYAPP_DB_<REST> adds <rest>, lowercased, as a
+//     single entry: MYAPP_DB_POOL_SIZE adds pool_size.
+//
+// The map is returned even when only env vars provide entries for it.
 func (c *ConfigManager) GetStringMap(key string) map[string]any {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-	v, ok := c.combinedConfig[strings.ToLower(key)]
-	if !ok {
-		v, ok = c.envConfig[strings.ToLower(c.envPrefix+key)]
-		if !ok {
+	v, ok := c.lookup(key)
+	var m map[string]any
+	if ok {
+		if m, ok = toStringMap(v.Value); !ok {
 			return nil
 		}
+		m = normalizeMapKeys(m).(map[string]any)
 	}
-	switch val := v.Value.(type) {
-	case map[string]any:
-		return val
-	default:
+	c.mutex.RLock()
+	env := c.envSubkeys(strings.ToLower(key), m)
+	c.mutex.RUnlock()
+	if m == nil && len(env) == 0 {
+		return nil
+	}
+	if m == nil {
+		m = make(map[string]any, len(env))
+	}
+	for k, v := range env {
+		m[k] = v
+	}
+	return m
+}
+
+// GetStringMapString returns GetStringMap(key) with values formatted as
+// strings.
+func (c *ConfigManager) GetStringMapString(key string) map[string]string {
+	m := c.GetStringMap(key)
+	if m == nil {
+		return nil
+	}
+	ret := make(map[string]string, len(m))
+	for k, v := range m {
+		ret[k] = fmt.Sprintf("%v", v)
+	}
+	return ret
+}
+
+// envSubkeys collects env values whose names extend the env name of lower
+// with an underscore, skipping those that name an entry already in m. The
+// caller must hold the lock.
+func (c *ConfigManager) envSubkeys(lower string, m map[string]any) map[string]any {
+	if !c.automaticEnv {
 		return nil
 	}
+	existing := make(map[string]bool)
+	flat := make(map[string]any)
+	flattenInto(flat, lower, m)
+	for k := range flat {
+		k = strings.ToLower(k)
+		existing[c.envKey(k)] = true
+		existing[strings.ReplaceAll(k, ".", "_")] = true
+	}
+	var sub map[string]any
+	for _, prefix := range []string{c.envKey(lower) + "_", strings.ReplaceAll(lower, ".", "_") + "_"} {
+		for k := range c.envConfig {
+			if !strings.HasPrefix(k, prefix) || len(k) == len(prefix) || existing[k] {
+				continue
+			}
+			if sub == nil {
+				sub = make(map[string]any)
+			}
+			v, _ := c.envValue(k)
+			sub[strings.TrimPrefix(k, prefix)] = v.Value
+		}
+	}
+	return sub
 }
 
 func (c *ConfigManager) GetStringSlice(key string) []string {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-	v, ok := c.combinedConfig[strings.ToLower(key)]
+	v, ok := c.lookup(key)
 	if !ok {
-		v, ok = c.envConfig[strings.ToLower(c.envPrefix+key)]
-		if !ok {
-			return nil
-		}
+		return nil
 	}
-	switch val := v.Value.(type) {
+	return c.toStringSlice(v.Value)
+}
+
+func (c *ConfigManager) toStringSlice(in any) []string {
+	switch val := in.(type) {
+	case []string:
+		return val
 	case []any:
 		var ret []string
 		for _, v := range val {
@@ -154,76 +287,309 @@ func (c *ConfigManager) GetStringSlice(key string) []string {
 			}
 		}
 		return ret
+	case string:
+		return c.splitSlice(val)
+	case nil:
+		return nil
 	default:
+		return toSlice(val, func(e any) (string, error) {
+			return fmt.Sprintf("%v", e), nil
+		})
+	}
+}
+
+// SetSliceSeparator sets the separator used when GetStringSlice reads a
+// plain string, such as an env var, as a list. The default is a comma.
+func (c *ConfigManager) SetSliceSeparator(sep string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	c.sliceSeparator = sep
+}
+
+func (c *ConfigManager) splitSlice(s string) []string {
+	c.mutex.RLock()
+	sep := c.sliceSeparator
+	c.mutex.RUnlock()
+	if sep == "" {
+		sep = ","
+	}
+	if strings.TrimSpace(s) == "" {
 		return nil
 	}
+	parts := strings.Split(s, sep)
+	for i, p := range parts {
+		parts[i] = strings.TrimSpace(p)
+	}
+	return parts
 }
 
 func (c *ConfigManager) GetInt(key string) int {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
-	v, ok := c.combinedConfig[strings.ToLower(key)]
+	i, err := c.GetIntE(key)
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
+func (c *ConfigManager) GetIntE(key string) (int, error) {
+	v, ok := c.lookup(key)
 	if !ok {
-		v, ok = c.envConfig[strings.ToLower(c.envPrefix+key)]
-		if !ok {
-			return 0
-		}
+		return 0, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
 	}
-	switch val := v.Value.(type) {
-	case int:
+	i, err := toInt(c.intInput(v.Value), c.getIntRounding())
+	if err != nil {
+		return 0, fmt.Errorf("key %s: %w", key, err)
+	}
+	return i, nil
+}
+
+func (c *ConfigManager) GetInt64(key string) int64 {
+	i, err := c.GetInt64E(key)
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
+func (c *ConfigManager) GetInt64E(key string) (int64, error) {
+	v, ok := c.lookup(key)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	i, err := toInt64(c.intInput(v.Value), c.getIntRounding())
+	if err != nil {
+		return 0, fmt.Errorf("key %s: %w", key, err)
+	}
+	return i, nil
+}
+
+// GetIntSlice returns the list at key as ints. Elements are converted like
+// GetInt, so numeric strings and floats are accepted; elements that cannot
+// be converted are skipped. A plain string is split as in GetStringSlice.
+func (c *ConfigManager) GetIntSlice(key string) []int {
+	v, ok := c.lookup(key)
+	if !ok {
+		return nil
+	}
+	if val, ok := v.Value.([]int); ok {
 		return val
-	case string:
-		i, err := strconv.Atoi(val)
-		if err != nil {
-			return 0
+	}
+	return intSlice(c, v.Value, toInt)
+}
+
+// intSlice converts each element of in with conv, after stripping thousands
+// commas as the integer getters do. A plain string is split as in
+// GetStringSlice. Elements that are nil or cannot be converted are skipped.
+func intSlice[T any](c *ConfigManager, in any, conv func(any, IntRounding) (T, error)) []T {
+	if s, ok := in.(string); ok {
+		in = c.splitSlice(s)
+	}
+	rounding := c.getIntRounding()
+	return toSlice(in, func(e any) (T, error) {
+		if e == nil {
+			var zero T
+			return zero, fmt.Errorf("nil element")
 		}
-		return i
-	case float32:
-		return int(val)
-	case float64:
-		return int(val)
-	case nil:
-		return 0
-	default:
+		return conv(c.intInput(e), rounding)
+	})
+}
+
+// GetEnum matches the value at key against allowed case-insensitively and
+// returns the matching element of allowed.
+func (c *ConfigManager) GetEnum(key string, allowed []string) (string, error) {
+	v, ok := c.lookup(key)
+	if !ok || v.Value == nil {
+		return "", fmt.Errorf("%w: %s, must be one of: %s", ErrKeyNotFound, key, strings.Join(allowed, ", "))
+	}
+	val := fmt.Sprintf("%v", v.Value)
+	for _, a := range allowed {
+		if strings.EqualFold(val, a) {
+			return a, nil
+		}
+	}
+	return "", fmt.Errorf("invalid value %q for key %s, must be one of: %s", val, key, strings.Join(allowed, ", "))
+}
+
+func (c *ConfigManager) GetPercent(key string) float64 {
+	p, err := c.GetPercentE(key)
+	if err != nil {
 		return 0
 	}
+	return p
 }
 
-func (c *ConfigManager) GetIntSlice(key string) []int {
+// GetPercentE returns the value at key as a fraction between 0 and 1.
+// Strings with a trailing % are divided by 100; bare numbers are used as-is.
+func (c *ConfigManager) GetPercentE(key string) (float64, error) {
+	v, ok := c.lookup(key)
+	if !ok || v.Value == nil {
+		return 0, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	var p float64
+	if s, ok := v.Value.(string); ok && strings.HasSuffix(strings.TrimSpace(s), "%") {
+		f, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "%")), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid percentage %q for key %s: %w", s, key, err)
+		}
+		p = f / 100
+	} else {
+		f, err := toFloat(v.Value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid percentage for key %s: %w", key, err)
+		}
+		p = f
+	}
+	if p < 0 || p > 1 {
+		return 0, fmt.Errorf("percentage %v for key %s is out of range", v.Value, key)
+	}
+	return p, nil
+}
+
+// Range calls fn for each key in the effective config until fn returns
+// false. The read lock is held for the duration, so fn must not modify the
+// ConfigManager.
+func (c *ConfigManager) Range(fn func(key string, value any) bool) {
+	c.ensureCollapsed()
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
-	v, ok := c.combinedConfig[strings.ToLower(key)]
-	if !ok {
-		v, ok = c.envConfig[strings.ToLower(c.envPrefix+key)]
-		if !ok {
-			return nil
+	for k, v := range c.combinedConfig {
+		if !fn(k, v.Value) {
+			return
 		}
 	}
+}
+
+// AllKeys returns the sorted, lowercased keys of the effective config.
+func (c *ConfigManager) AllKeys() []string {
+	c.ensureCollapsed()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	keys := make([]string, 0, len(c.combinedConfig))
+	for k := range c.combinedConfig {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// AllSettings returns a deep copy of the effective config keyed by
+// lowercased key. Dotted keys such as "db.host" are moved into the map at
+// their root key, as when unmarshaling.
+func (c *ConfigManager) AllSettings() map[string]any {
+	c.ensureCollapsed()
+	c.mutex.RLock()
+	settings := make(map[string]any, len(c.combinedConfig))
+	for k, v := range c.combinedConfig {
+		settings[k] = deepCopy(v.Value)
+	}
+	c.mutex.RUnlock()
+	nestDottedKeys(settings)
+	return settings
+}
+
+// AllSettingsOriginalCase returns the effective config keyed by each key as
+// it was originally written rather than lowercased.
+func (c *ConfigManager) AllSettingsOriginalCase() map[string]any {
+	c.ensureCollapsed()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	settings := make(map[string]any, len(c.combinedConfig))
+	for _, v := range c.combinedConfig {
+		settings[v.Key] = v.Value
+	}
+	return settings
+}
+
+func (c *ConfigManager) GetSlice(key string) []any {
+	v, ok := c.lookup(key)
+	if !ok {
+		return nil
+	}
 	switch val := v.Value.(type) {
 	case []any:
-		var ret []int
-		for _, v := range val {
-			switch v := v.(type) {
-			case int:
-				ret = append(ret, v)
-			case string:
-				i, err := strconv.Atoi(v)
-				if err != nil {
-					continue
-				}
-				ret = append(ret, i)
-			case float32:
-				ret = append(ret, int(v))
-			case float64:
-				ret = append(ret, int(v))
-			case nil:
-				continue
-			default:
-				continue
-			}
+		return val
+	case nil:
+		return nil
+	default:
+		rv := reflect.ValueOf(val)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil
+		}
+		ret := make([]any, rv.Len())
+		for i := range ret {
+			ret[i] = rv.Index(i).Interface()
 		}
 		return ret
-	default:
+	}
+}
+
+// IsSet reports whether key resolves to a value from any source, including
+// environment variables that have no corresponding default or file entry.
+// Keys given only a value with SetDefault are set, as are empty strings,
+// zero values and keys explicitly set to null, such as `key:` in YAML.
+func (c *ConfigManager) IsSet(key string) bool {
+	_, ok := c.lookup(key)
+	return ok
+}
+
+// IsNull reports whether key is present but explicitly null, telling it
+// apart from a key that is missing.
+func (c *ConfigManager) IsNull(key string) bool {
+	v, ok := c.lookup(key)
+	return ok && v.Value == nil
+}
+
+func (c *ConfigManager) FlattenStringMap(key string) map[string]any {
+	v, ok := c.lookup(key)
+	if !ok {
+		return nil
+	}
+	m, ok := toStringMap(v.Value)
+	if !ok {
+		return nil
+	}
+	flat := make(map[string]any)
+	flattenInto(flat, "", m)
+	return flat
+}
+
+func (c *ConfigManager) GetUint64(key string) uint64 {
+	u, err := c.GetUint64E(key)
+	if err != nil {
+		return 0
+	}
+	return u
+}
+
+func (c *ConfigManager) GetUint64E(key string) (uint64, error) {
+	v, ok := c.lookup(key)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	u, err := toUint64(c.intInput(v.Value), c.getIntRounding())
+	if err != nil {
+		return 0, fmt.Errorf("key %s: %w", key, err)
+	}
+	return u, nil
+}
+
+// GetInt64Slice is like GetIntSlice for int64 values.
+func (c *ConfigManager) GetInt64Slice(key string) []int64 {
+	v, ok := c.lookup(key)
+	if !ok {
+		return nil
+	}
+	return intSlice(c, v.Value, toInt64)
+}
+
+// GetUint64Slice is like GetIntSlice for uint64 values.
+func (c *ConfigManager) GetUint64Slice(key string) []uint64 {
+	v, ok := c.lookup(key)
+	if !ok {
 		return nil
 	}
+	return intSlice(c, v.Value, toUint64)
 }
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSlicesFromMixedArrays(t *testing.T) {
+	tests := []struct {
+		configType string
+		data       string
+	}{
+		{"yaml", "ints: [1, 2.0, \"3\", x, \" 4 \"]\nstrings: [a, 1, true, 2.5]\n"},
+		{"json", `{"ints": [1, 2.0, "3", "x", " 4 "], "strings": ["a", 1, true, 2.5]}`},
+		{"toml", "ints = [1, 2.0, \"3\", \"x\", \" 4 \"]\nstrings = [\"a\", 1, true, 2.5]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.configType, func(t *testing.T) {
+			c := NewConfigManager()
+			if err := c.SetConfigType(tt.configType); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.ReadConfig(strings.NewReader(tt.data)); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := c.GetIntSlice("ints"), []int{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+				t.Errorf("GetIntSlice(ints) = %v, want %v", got, want)
+			}
+			if got, want := c.GetInt64Slice("ints"), []int64{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+				t.Errorf("GetInt64Slice(ints) = %v, want %v", got, want)
+			}
+			if got, want := c.GetUint64Slice("ints"), []uint64{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+				t.Errorf("GetUint64Slice(ints) = %v, want %v", got, want)
+			}
+			if got, want := c.GetStringSlice("strings"), []string{"a", "1", "true", "2.5"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("GetStringSlice(strings) = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSlicesFromEnvString(t *testing.T) {
+	t.Setenv("IDS", "1, 2,3")
+
+	c := NewConfigManager()
+	if got, want := c.GetIntSlice("ids"), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetIntSlice(ids) = %v, want %v", got, want)
+	}
+	if got, want := c.GetInt64Slice("ids"), []int64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetInt64Slice(ids) = %v, want %v", got, want)
+	}
+	if got, want := c.GetUint64Slice("ids"), []uint64{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetUint64Slice(ids) = %v, want %v", got, want)
+	}
+}
+
+func TestTypedSlices(t *testing.T) {
+	c := NewConfigManager()
+	if err := c.Set("ints", []int{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("int64s", []int64{3, 4}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.GetIntSlice("ints"), []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetIntSlice(ints) = %v, want %v", got, want)
+	}
+	if got, want := c.GetIntSlice("int64s"), []int{3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetIntSlice(int64s) = %v, want %v", got, want)
+	}
+	if got, want := c.GetStringSlice("int64s"), []string{"3", "4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetStringSlice(int64s) = %v, want %v", got, want)
+	}
+}
+
+func TestGetFloat64E(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    float64
+		wantErr bool
+	}{
+		{"yaml float", "rate: 0.25\n", 0.25, false},
+		{"string", "rate: \"0.5\"\n", 0.5, false},
+		{"empty string", "rate: \"\"\n", 0, true},
+		{"non-numeric string", "rate: fast\n", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfigManager()
+			if err := c.SetConfigType("yaml"); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.ReadConfig(strings.NewReader(tt.data)); err != nil {
+				t.Fatal(err)
+			}
+			got, err := c.GetFloat64E("rate")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFloat64E(rate) error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("GetFloat64E(rate) = %v, want %v", got, tt.want)
+			}
+			if got := c.GetFloat64("rate"); got != tt.want {
+				t.Errorf("GetFloat64(rate) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// inferConfigType determines the config type from the file extension,
+// looking past a trailing .gz.
+func inferConfigType(filename string) (configType, error) {
+	return parseConfigType(filepath.Ext(strings.TrimSuffix(filename, ".gz")))
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g gzipFile) Close() error {
+	g.Reader.Close()
+	return g.f.Close()
+}
+
+// openConfigFile opens filename for reading, transparently decompressing
+// files with a .gz extension.
+func openConfigFile(filename string) (io.ReadCloser, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(filename, ".gz") {
+		return f, nil
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return gzipFile{Reader: zr, f: f}, nil
+}
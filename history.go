@@ -0,0 +1,61 @@
+package config
+
+import "time"
+
+type ChangeRecord struct {
+	Time time.Time
+	Key  string
+	Old  any
+	New  any
+}
+
+// EnableHistory keeps the last size changes to the effective config. A size
+// of zero disables recording and discards the history.
+func (c *ConfigManager) EnableHistory(size int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.historySize = size
+	if size <= 0 {
+		c.history = nil
+	} else if len(c.history) > size {
+		c.history = append([]ChangeRecord(nil), c.history[len(c.history)-size:]...)
+	}
+}
+
+func (c *ConfigManager) History() []ChangeRecord {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return append([]ChangeRecord(nil), c.history...)
+}
+
+// recordChanges appends a record for every key whose value differs between
+// oldConfig and newConfig. The caller must hold the write lock.
+func (c *ConfigManager) recordChanges(oldConfig, newConfig map[string]ConfigMap) {
+	if c.historySize <= 0 {
+		return
+	}
+	now := time.Now()
+	for _, d := range diffConfig(oldConfig, newConfig) {
+		c.history = append(c.history, ChangeRecord{Time: now, Key: d.Key, Old: d.Old, New: d.New})
+	}
+	if len(c.history) > c.historySize {
+		c.history = append([]ChangeRecord(nil), c.history[len(c.history)-c.historySize:]...)
+	}
+}
+
+// recordChange records a change to a single key. The caller must hold the
+// write lock.
+func (c *ConfigManager) recordChange(lower string, old ConfigMap, hadOld bool) {
+	if c.historySize <= 0 {
+		return
+	}
+	oldConfig := map[string]ConfigMap{}
+	if hadOld {
+		oldConfig[lower] = old
+	}
+	newConfig := map[string]ConfigMap{}
+	if v, ok := c.find(lower); ok {
+		newConfig[lower] = v
+	}
+	c.recordChanges(oldConfig, newConfig)
+}
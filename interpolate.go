@@ -0,0 +1,124 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInterpolationCycle = errors.New("interpolation cycle")
+
+	interpolationRef = regexp.MustCompile(`\$\{([^}]+)\}`)
+)
+
+// EnableInterpolation expands ${other.key} references in string values
+// against the effective config whenever a config is read. References to
+// keys that are not set are left in place verbatim.
+func (c *ConfigManager) EnableInterpolation(enable bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	c.interpolation = enable
+}
+
+// interpolate expands references in combinedConfig. The caller must hold the
+// write lock.
+func (c *ConfigManager) interpolate() error {
+	resolved := make(map[string]any)
+	ccm := make(map[string]ConfigMap, len(c.combinedConfig))
+	for k, v := range c.combinedConfig {
+		val, err := c.expandKey(k, resolved, nil)
+		if err != nil {
+			return err
+		}
+		ccm[k] = ConfigMap{Key: v.Key, Value: val}
+	}
+	c.combinedConfig = ccm
+	return nil
+}
+
+func (c *ConfigManager) expandKey(key string, resolved map[string]any, stack []string) (any, error) {
+	if v, ok := resolved[key]; ok {
+		return v, nil
+	}
+	for i, k := range stack {
+		if k == key {
+			return nil, fmt.Errorf("%w: %s", ErrInterpolationCycle, strings.Join(append(stack[i:], key), " -> "))
+		}
+	}
+	// references resolve within the config itself, never from env vars
+	// that no key picked up
+	v, ok := c.combinedConfig[key]
+	if !ok {
+		if v, ok = c.lookupNested(key); !ok {
+			return nil, nil
+		}
+	}
+	val, err := c.expandValue(v.Value, resolved, append(stack, key))
+	if err != nil {
+		return nil, err
+	}
+	resolved[key] = val
+	return val, nil
+}
+
+func (c *ConfigManager) expandValue(in any, resolved map[string]any, stack []string) (any, error) {
+	switch val := in.(type) {
+	case string:
+		return c.expandString(val, resolved, stack)
+	case map[string]any:
+		m := make(map[string]any, len(val))
+		for k, v := range val {
+			e, err := c.expandValue(v, resolved, stack)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = e
+		}
+		return m, nil
+	case []any:
+		s := make([]any, len(val))
+		for i, v := range val {
+			e, err := c.expandValue(v, resolved, stack)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = e
+		}
+		return s, nil
+	default:
+		return in, nil
+	}
+}
+
+func (c *ConfigManager) expandString(s string, resolved map[string]any, stack []string) (any, error) {
+	matches := interpolationRef.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		ref := strings.ToLower(strings.TrimSpace(s[m[2]:m[3]]))
+		val, err := c.expandKey(ref, resolved, stack)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(s[last:m[0]])
+		if val == nil {
+			b.WriteString(s[m[0]:m[1]])
+		} else if m[0] == 0 && m[1] == len(s) {
+			// a value that is only a reference keeps the referenced type
+			return val, nil
+		} else {
+			fmt.Fprintf(&b, "%v", val)
+		}
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String(), nil
+}
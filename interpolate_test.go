@@ -0,0 +1,63 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterpolation(t *testing.T) {
+	t.Setenv("SECRET_TOKEN", "from-env")
+
+	c := NewConfigManager()
+	c.EnableInterpolation(true)
+	if err := c.SetConfigType("yaml"); err != nil {
+		t.Fatal(err)
+	}
+	data := `
+host: example.com
+db:
+  port: 5432
+url: https://${host}:${db.port}
+port: ${db.port}
+token: ${secret_token}
+`
+	if err := c.ReadConfig(strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key  string
+		want any
+	}{
+		{"url", "https://example.com:5432"},
+		{"port", 5432},
+		{"token", "${secret_token}"},
+	}
+	for _, tt := range tests {
+		if got := c.Get(tt.key); got != tt.want {
+			t.Errorf("Get(%q) = %#v, want %#v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestInterpolationSurvivesSet(t *testing.T) {
+	c := NewConfigManager()
+	c.EnableInterpolation(true)
+	if err := c.SetConfigType("yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadConfig(strings.NewReader("host: h\ndb.url: \"${host}/x\"\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("db.port", 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("db.url"); got != "h/x" {
+		t.Errorf("GetString(db.url) = %q, want h/x", got)
+	}
+	if err := c.Set("host", "other"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("db.url"); got != "other/x" {
+		t.Errorf("after Set(host), GetString(db.url) = %q, want other/x", got)
+	}
+}
@@ -1,26 +1,41 @@
 package config
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v3"
 )
 
 const (
-	ConfigTypeTOML configType = "toml"
-	ConfigTypeYAML configType = "yaml"
-	ConfigTypeJSON configType = "json"
+	ConfigTypeTOML       configType = "toml"
+	ConfigTypeYAML       configType = "yaml"
+	ConfigTypeJSON       configType = "json"
+	ConfigTypeProperties configType = "properties"
+)
+
+const (
+	IntTruncate IntRounding = iota
+	IntRound
+	IntCeil
 )
 
 type (
 	configType string
 
+	IntRounding int
+
 	ConfigMap struct {
 		Key   string
 		Value any
@@ -32,18 +47,65 @@ type (
 		configFileUsed   string
 		configType       configType
 		envPrefix        string
+		envPrefixes      []string
+		envNames         map[string]string
+		envBindings      map[string]envBinding
+		bareEnv          map[string]ConfigMap
 		mapConfig        map[string]ConfigMap
 		defaultConfig    map[string]ConfigMap
 		envConfig        map[string]ConfigMap
 		combinedConfig   map[string]ConfigMap
+		comments         map[string]string
+		durationUnit     time.Duration
+		keyDurationUnits map[string]time.Duration
+		envKeyReplacer   *strings.Replacer
+		intRounding      IntRounding
+		thousandsCommas  bool
+		floatParsing     FloatParsing
+		sliceSeparator   string
+		jsonPrefix       string
+		jsonIndent       string
+		yamlIndent       int
 		mutex            sync.RWMutex
+		writeMutex       sync.Mutex
 		explicitDefaults bool
+		standardPaths    bool
+		interpolation    bool
+		mergeOnRead      bool
+		deepMergeMaps    bool
+		listMergeKeys    map[string]string
+		automaticEnv     bool
+		envTypeInference bool
+		envOnly          bool
+		caseCollision    bool
+		frozen           bool
+		collapsed        bool
+		history          []ChangeRecord
+		historySize      int
+		schema           reflect.Type
+		types            map[string]reflect.Kind
+		searchedPaths    []string
+		secretsFile      string
+		secretConfig     map[string]ConfigMap
+		argConfig        map[string]ConfigMap
+		bestEffort       bool
+		warnings         chan error
+		trackUsage       bool
+		usedKeys         map[string]bool
+		usageMutex       sync.Mutex
 	}
 )
 
 var (
 	ErrConfigFileNotFound = errors.New("config file not found")
 	ErrConfigFileEmpty    = errors.New("config file is empty")
+	ErrConfigFileExists   = errors.New("config file already exists")
+	ErrNoConfigFile       = errors.New("no config file or config name set")
+	ErrFrozen             = errors.New("config is frozen")
+	ErrUnsupportedType    = errors.New("unsupported value type")
+	ErrConfigTypeUnset    = errors.New("config type not set: call SetConfigType or use a .toml, .yaml, .json or .properties extension")
+	ErrKeyNotFound        = errors.New("key not found")
+	ErrWrongType          = errors.New("value has the wrong type")
 )
 
 func NewConfigManager() *ConfigManager {
@@ -52,31 +114,132 @@ func NewConfigManager() *ConfigManager {
 	cm.mapConfig = make(map[string]ConfigMap)
 	cm.defaultConfig = make(map[string]ConfigMap)
 	cm.combinedConfig = make(map[string]ConfigMap)
+	cm.comments = make(map[string]string)
+	cm.durationUnit = time.Nanosecond
+	cm.jsonIndent = "  "
 	cm.envPrefix = ""
-	envSet := os.Environ()
-	for _, env := range envSet {
-		kv := strings.Split(env, "=")
-		lower := strings.ToLower(kv[0])
-		cm.envConfig[lower] = ConfigMap{Key: kv[0], Value: kv[1]}
+	cm.automaticEnv = true
+	cm.loadEnv()
+	return &cm
+}
+
+// NewFromMap returns a ConfigManager holding m as its config, with
+// environment resolution disabled.
+func NewFromMap(m map[string]any) *ConfigManager {
+	cm := ConfigManager{}
+	cm.envConfig = make(map[string]ConfigMap)
+	cm.mapConfig = make(map[string]ConfigMap, len(m))
+	cm.defaultConfig = make(map[string]ConfigMap)
+	cm.comments = make(map[string]string)
+	cm.durationUnit = time.Nanosecond
+	cm.jsonIndent = "  "
+	for k, v := range m {
+		cm.mapConfig[strings.ToLower(k)] = ConfigMap{Key: k, Value: v}
 	}
+	cm.collapse()
 	return &cm
 }
 
+// NewEnvOnly returns a ConfigManager that resolves keys from env vars under
+// prefix and from defaults only. Dots in keys map to underscores, so
+// database.host resolves from <prefix>_DATABASE_HOST. ReadInConfig does
+// nothing.
+func NewEnvOnly(prefix string) *ConfigManager {
+	cm := NewConfigManager()
+	cm.envOnly = true
+	cm.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	cm.SetEnvPrefix(prefix)
+	return cm
+}
+
 func (c *ConfigManager) WithEnvPrefix(prefix string) *ConfigManager {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	c.envPrefix = prefix
-	envSet := os.Environ()
+	c.SetEnvPrefix(prefix)
+	return c
+}
+
+// loadEnv captures the env vars under the env prefixes. A prefix is
+// separated from the key by an underscore, added if the prefix does not end
+// in one, and matched case-insensitively, so prefix APP resolves port from
+// APP_PORT. When a prefix is set, unprefixed env vars are kept as a
+// fallback for keys without a prefixed var. The caller must hold the write
+// lock.
+func (c *ConfigManager) loadEnv() {
 	c.envConfig = make(map[string]ConfigMap)
-	for _, env := range envSet {
-		kv := strings.Split(env, "=")
-		if strings.HasPrefix(kv[0], prefix) {
-			withoutPrefix := strings.TrimPrefix(kv[0], prefix)
+	c.envNames = make(map[string]string)
+	c.bareEnv = nil
+	prefixes := c.envPrefixes
+	if len(prefixes) == 0 {
+		prefixes = []string{c.envPrefix}
+	}
+	environ := os.Environ()
+	// walk the prefixes from last to first so earlier prefixes win
+	for i := len(prefixes) - 1; i >= 0; i-- {
+		prefix := prefixes[i]
+		if prefix != "" && !strings.HasSuffix(prefix, "_") {
+			prefix += "_"
+		}
+		for _, env := range environ {
+			key, value, _ := strings.Cut(env, "=")
+			if len(key) <= len(prefix) || !strings.EqualFold(key[:len(prefix)], prefix) {
+				continue
+			}
+			withoutPrefix := key[len(prefix):]
 			lower := strings.ToLower(withoutPrefix)
-			c.envConfig[lower] = ConfigMap{Key: withoutPrefix, Value: kv[1]}
+			c.envConfig[lower] = ConfigMap{Key: withoutPrefix, Value: value}
+			c.envNames[lower] = key
 		}
 	}
-	return c
+	if c.envPrefix == "" {
+		return
+	}
+	c.bareEnv = make(map[string]ConfigMap)
+	for _, env := range environ {
+		key, value, _ := strings.Cut(env, "=")
+		c.bareEnv[strings.ToLower(key)] = ConfigMap{Key: key, Value: value}
+	}
+}
+
+func (c *ConfigManager) AutomaticEnv() {
+	c.SetAutomaticEnv(true)
+}
+
+func (c *ConfigManager) SetAutomaticEnv(enable bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	previous := c.automaticEnv
+	c.automaticEnv = enable
+	if c.collapseLocked() != nil {
+		c.automaticEnv = previous
+	}
+}
+
+// envValue returns the captured environment value for lower, if automatic
+// env resolution is enabled. The caller must hold the lock.
+func (c *ConfigManager) envValue(lower string) (ConfigMap, bool) {
+	if !c.automaticEnv {
+		return ConfigMap{}, false
+	}
+	v, ok := c.envConfig[lower]
+	if !ok {
+		v, ok = c.bareEnv[lower]
+	}
+	if ok && c.envTypeInference {
+		v.Value = inferType(v.Value)
+	}
+	return v, ok
+}
+
+// Freeze makes the config immutable: from then on, setters and reads of new
+// config return ErrFrozen while getters keep working. Settings that return
+// no error, such as SetEnvPrefix, SetDurationUnit, RegisterType and
+// EnableInterpolation, are ignored.
+func (c *ConfigManager) Freeze() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.frozen = true
 }
 
 func (c *ConfigManager) ConfigFileUsed() string {
@@ -91,126 +254,555 @@ func (c *ConfigManager) UseExplicitDefaults(enable bool) {
 	c.explicitDefaults = enable
 }
 
-func (c *ConfigManager) collapse() {
+// ensureCollapsed computes the effective config on first use, so a manager
+// driven only by env and defaults never needs a config file to be read.
+func (c *ConfigManager) ensureCollapsed() {
 	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+	collapsed := c.collapsed
+	c.mutex.RUnlock()
+	if !collapsed {
+		c.collapse()
+	}
+}
+
+// resolve merges the layers holding lower, from defaults and env up to
+// command-line args. The caller must hold the lock.
+func (c *ConfigManager) resolve(lower string) (ConfigMap, bool) {
+	v, ok := c.defaultConfig[lower]
+	if ok {
+		env, found := c.envValue(c.envKey(lower))
+		if !found {
+			env, found = c.envValue(lower)
+		}
+		if found {
+			v = c.envOverDefault(v, env)
+		}
+	}
+	if env, _, found := c.boundEnv(lower); found {
+		if ok {
+			env = c.envOverDefault(v, env)
+		}
+		v, ok = env, true
+	}
+	if m, found := c.mapConfig[lower]; found {
+		v, ok = c.overDefault(lower, m), true
+	}
+	if s, found := c.secretConfig[lower]; found {
+		if ok {
+			s = ConfigMap{Key: v.Key, Value: c.mergeAt(lower, v.Value, s.Value)}
+		}
+		v, ok = s, true
+	}
+	if a, found := c.argConfig[lower]; found {
+		if ok {
+			a = c.envOverDefault(v, a)
+		}
+		v, ok = a, true
+	}
+	return v, ok
+}
+
+func (c *ConfigManager) collapse() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.collapseLocked()
+}
+
+// collapseLocked rebuilds combinedConfig from the layers and records the
+// changes. If interpolation or type coercion fails, the previous
+// combinedConfig is kept. The caller must hold the write lock.
+func (c *ConfigManager) collapseLocked() error {
+	previous, wasCollapsed := c.combinedConfig, c.collapsed
+	if err := c.rebuild(); err != nil {
+		return err
+	}
+	c.collapsed = true
+	if wasCollapsed {
+		c.recordChanges(previous, c.combinedConfig)
+	}
+	return nil
+}
+
+// rebuild replaces combinedConfig with one merged from the layers,
+// interpolated and coerced to the registered types. If that fails, the
+// previous combinedConfig is kept. The caller must hold the write lock.
+func (c *ConfigManager) rebuild() error {
+	previous := c.combinedConfig
 	ccm := make(map[string]ConfigMap)
-	for k, v := range c.defaultConfig {
-		ccm[k] = v
-		if _, ok := c.envConfig[k]; ok {
-			ccm[k] = c.envConfig[k]
+	for _, layer := range []map[string]ConfigMap{c.defaultConfig, c.mapConfig, c.secretConfig, c.argConfig} {
+		for k := range layer {
+			ccm[k], _ = c.resolve(k)
 		}
 	}
-	for k, v := range c.mapConfig {
-		ccm[k] = v
+	for k := range c.envBindings {
+		if v, ok := c.resolve(k); ok {
+			ccm[k] = v
+		}
 	}
+	c.applyNestedOverrides(ccm)
 	c.combinedConfig = ccm
+	var err error
+	if c.interpolation {
+		err = c.interpolate()
+	}
+	if err == nil {
+		err = c.coerceTypes()
+	}
+	if err != nil {
+		c.combinedConfig = previous
+		return err
+	}
+	return nil
 }
 
 func (c *ConfigManager) WriteConfig() error {
+	c.ensureCollapsed()
+	return c.writeConfig(func() map[string]ConfigMap { return c.withoutSecrets(c.combinedConfig) })
+}
+
+func (c *ConfigManager) WriteConfigMinimal() error {
+	return c.writeConfig(func() map[string]ConfigMap { return c.mapConfig })
+}
+
+func (c *ConfigManager) WriteConfigTo(w io.Writer, configType string) error {
+	ct, err := parseConfigType(configType)
+	if err != nil {
+		return err
+	}
+	c.ensureCollapsed()
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.encodeConfig(w, c.withoutSecrets(c.combinedConfig), ct)
+}
+
+// MarshalString encodes the effective config in the current config type,
+// as WriteConfig would write it.
+func (c *ConfigManager) MarshalString() (string, error) {
+	c.ensureCollapsed()
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
+	ct := c.configType
+	if ct == "" {
+		var err error
+		if ct, err = inferConfigType(c.configFileUsed); err != nil {
+			return "", ErrConfigTypeUnset
+		}
+	}
+	var buf bytes.Buffer
+	if err := c.encodeConfig(&buf, c.withoutSecrets(c.combinedConfig), ct); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// writeConfig encodes the config returned by source under the read lock and
+// atomically replaces the config file with it. Writes are serialized by
+// writeMutex so concurrent saves cannot interleave.
+func (c *ConfigManager) writeConfig(source func() map[string]ConfigMap) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	c.mutex.RLock()
+	filename := c.configFileUsed
+	if filename == "" {
+		c.mutex.RUnlock()
+		return ErrNoConfigFile
+	}
+	ct := c.configType
+	if ct == "" {
+		var err error
+		if ct, err = inferConfigType(filename); err != nil {
+			c.mutex.RUnlock()
+			return fmt.Errorf("unable to determine config type from path %s: %w", filename, ErrConfigTypeUnset)
+		}
+	}
+	if _, err := parseConfigType(string(ct)); err != nil {
+		c.mutex.RUnlock()
+		return err
+	}
+	var buf bytes.Buffer
+	var err error
+	if strings.HasSuffix(filename, ".gz") {
+		zw := gzip.NewWriter(&buf)
+		if err = c.encodeConfig(zw, source(), ct); err == nil {
+			err = zw.Close()
+		}
+	} else {
+		err = c.encodeConfig(&buf, source(), ct)
+	}
+	c.mutex.RUnlock()
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filename, buf.Bytes())
+}
+
+func writeFileAtomic(filename string, data []byte) error {
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+	f, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+func (c *ConfigManager) encodeConfig(w io.Writer, config map[string]ConfigMap, ct configType) error {
 	flattenedConfig := make(map[string]any)
-	for _, v := range c.combinedConfig {
-		flattenedConfig[v.Key] = v.Value
+	for _, v := range config {
+		flattenedConfig[v.Key] = normalizeNumbers(v.Value)
 	}
-	switch c.configType {
+	nestDottedKeys(flattenedConfig)
+	switch ct {
 	case ConfigTypeTOML:
-		f, err := os.Create(c.configFileUsed)
-		if err != nil {
+		var buf bytes.Buffer
+		enc := toml.NewEncoder(&buf)
+		if err := enc.Encode(flattenedConfig); err != nil {
 			return err
 		}
-		defer f.Close()
-		enc := toml.NewEncoder(f)
-		err = enc.Encode(flattenedConfig)
+		_, err := w.Write(c.addTOMLComments(buf.Bytes()))
 		return err
 	case ConfigTypeYAML:
-		f, err := os.Create(c.configFileUsed)
+		node, err := c.yamlNode(flattenedConfig)
 		if err != nil {
 			return err
 		}
-		defer f.Close()
-		enc := yaml.NewEncoder(f)
-		err = enc.Encode(flattenedConfig)
+		enc := yaml.NewEncoder(w)
+		if c.yamlIndent > 0 {
+			enc.SetIndent(c.yamlIndent)
+		}
+		err = enc.Encode(node)
 		return err
 	case ConfigTypeJSON:
-		f, err := os.Create(c.configFileUsed)
-		if err != nil {
-			return err
-		}
-		defer f.Close()
-		enc := json.NewEncoder(f)
+		// encoding/json sorts map keys, so output is stable across writes.
+		enc := json.NewEncoder(w)
+		enc.SetIndent(c.jsonPrefix, c.jsonIndent)
+		enc.SetEscapeHTML(false)
 		return enc.Encode(flattenedConfig)
+	case ConfigTypeProperties:
+		return encodeProperties(w, flattenedConfig)
 	default:
-		return fmt.Errorf("config type %s not supported", c.configType)
+		return fmt.Errorf("config type %s not supported", ct)
 	}
 }
 
-func (c *ConfigManager) SetConfigType(configType string) error {
-	switch configType {
+func parseConfigType(name string) (configType, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
 	case "toml":
-		c.configType = ConfigTypeTOML
-	case "yaml":
-		c.configType = ConfigTypeYAML
+		return ConfigTypeTOML, nil
+	case "yaml", "yml":
+		return ConfigTypeYAML, nil
 	case "json":
-		c.configType = ConfigTypeJSON
+		return ConfigTypeJSON, nil
+	case "properties", "props":
+		return ConfigTypeProperties, nil
 	default:
-		return fmt.Errorf("config type %s not supported", configType)
+		return "", fmt.Errorf("config type %s not supported", name)
+	}
+}
+
+func (c *ConfigManager) SetConfigType(configType string) error {
+	ct, err := parseConfigType(configType)
+	if err != nil {
+		return err
 	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.configType = ct
 	return nil
 }
 
 func (c *ConfigManager) SetEnvPrefix(prefix string) {
-	c.envPrefix = prefix
+	c.SetEnvPrefixes(prefix)
+}
+
+// SetEnvPrefixes resolves env vars under each of prefixes. When the same key
+// is set under several prefixes, the earliest prefix wins, which allows
+// migrating from one prefix to another. EnvPrefix reports the first prefix.
+func (c *ConfigManager) SetEnvPrefixes(prefixes ...string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	oldPrefixes, oldPrefix := c.envPrefixes, c.envPrefix
+	envConfig, envNames, bareEnv := c.envConfig, c.envNames, c.bareEnv
+	c.envPrefixes = append([]string(nil), prefixes...)
+	c.envPrefix = ""
+	if len(prefixes) > 0 {
+		c.envPrefix = prefixes[0]
+	}
+	c.loadEnv()
+	if c.collapseLocked() != nil {
+		// keep the previous env if the new values fail type coercion
+		c.envPrefixes, c.envPrefix = oldPrefixes, oldPrefix
+		c.envConfig, c.envNames, c.bareEnv = envConfig, envNames, bareEnv
+	}
+}
+
+// SetEnvKeyReplacer rewrites keys before they are matched against the
+// environment, e.g. strings.NewReplacer(".", "_") resolves database.host
+// from DATABASE_HOST. Env var names are split on a plain underscore, so a
+// key segment that itself contains an underscore cannot be told apart from
+// a nested key.
+func (c *ConfigManager) SetEnvKeyReplacer(r *strings.Replacer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	c.envKeyReplacer = r
+}
+
+func (c *ConfigManager) envKey(lower string) string {
+	if c.envKeyReplacer == nil {
+		return lower
+	}
+	return strings.ToLower(c.envKeyReplacer.Replace(lower))
+}
+
+func (c *ConfigManager) SetIntRounding(mode IntRounding) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	c.intRounding = mode
+}
+
+// SetThousandsCommas makes integer getters accept commas between digits in
+// string values, as in 1,000,000. It is off by default because some locales
+// use commas as the decimal separator.
+func (c *ConfigManager) SetThousandsCommas(enable bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	c.thousandsCommas = enable
+}
+
+// intInput strips thousands commas from string values when enabled.
+func (c *ConfigManager) intInput(in any) any {
+	s, ok := in.(string)
+	if !ok || !strings.Contains(s, ",") {
+		return in
+	}
+	c.mutex.RLock()
+	enabled := c.thousandsCommas
+	c.mutex.RUnlock()
+	if !enabled {
+		return in
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == ',' && i > 0 && i+1 < len(s) && isDigit(s[i-1]) && isDigit(s[i+1]) {
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// SetJSONIndent sets the prefix and indent used when writing JSON config.
+// JSON is indented with two spaces by default; an empty indent writes each
+// document on a single line.
+func (c *ConfigManager) SetJSONIndent(prefix, indent string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.jsonPrefix = prefix
+	c.jsonIndent = indent
+}
+
+// SetYAMLIndent sets the number of spaces used for indentation when writing
+// YAML config. Values below 1 restore the encoder default.
+func (c *ConfigManager) SetYAMLIndent(n int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.yamlIndent = n
+}
+
+func (c *ConfigManager) getIntRounding() IntRounding {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.intRounding
+}
+
+func (c *ConfigManager) EnvPrefix() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.envPrefix
 }
 
 func (c *ConfigManager) ReadInConfig() error {
+	c.mutex.Lock()
+	if c.envOnly {
+		c.mutex.Unlock()
+		return nil
+	}
+	configType, err := c.findConfigFile()
+	configFile, schema, secretsFile := c.configFileUsed, c.schema, c.secretsFile
+	c.mutex.Unlock()
+	if err != nil {
+		return err
+	}
 	// assume config = map[string]any
-	confFileData, err := readFile(c.configFileUsed, c.configType)
+	confFileData, err := readFile(configFile, configType)
+	partial := false
+	var loadErr *LoadError
+	if errors.As(err, &loadErr) {
+		if salvaged, ok := c.salvageFile(configFile, configType, err); ok {
+			confFileData, err, partial = salvaged, nil, true
+		}
+	}
 	if err != nil {
 		return err
 	}
+	if schema != nil && !partial {
+		f, err := openConfigFile(configFile)
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		if err := validateSchema(data, configType, schema); err != nil {
+			return newLoadError(configFile, configType, err)
+		}
+	}
+	var secrets map[string]ConfigMap
+	if secretsFile != "" {
+		if secrets, err = readSecrets(secretsFile, configType); err != nil {
+			return err
+		}
+	}
+	return c.loadConfig(confFileData, secrets)
+}
+
+func (c *ConfigManager) ReadConfig(in io.Reader) error {
+	c.mutex.RLock()
+	configType, schema := c.configType, c.schema
+	c.mutex.RUnlock()
+	if configType == "" {
+		return ErrConfigTypeUnset
+	}
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	confData, err := decodeConfig(bytes.NewReader(data), configType)
+	if err != nil {
+		err = newLoadError("", configType, err)
+		salvaged, ok := c.salvage(data, configType, err)
+		if !ok {
+			return err
+		}
+		return c.loadConfig(salvaged, nil)
+	}
+	if schema != nil {
+		if err := validateSchema(data, configType, schema); err != nil {
+			return newLoadError("", configType, err)
+		}
+	}
+	return c.loadConfig(confData, nil)
+}
+
+func (c *ConfigManager) ReadConfigStdin() error {
+	return c.ReadConfig(os.Stdin)
+}
+
+// loadConfig replaces the config read from a file with data and, when
+// secrets is not nil, the secrets layer with secrets.
+func (c *ConfigManager) loadConfig(data map[string]any, secrets map[string]ConfigMap) error {
+	c.mutex.RLock()
+	caseCollision := c.caseCollision
+	c.mutex.RUnlock()
+	if caseCollision {
+		if err := checkCaseCollisions(data); err != nil {
+			return err
+		}
+	}
 	conf := make(map[string]ConfigMap)
-	for k, v := range confFileData {
+	for k, v := range data {
 		lower := strings.ToLower(k)
 		conf[lower] = ConfigMap{Key: k, Value: v}
 	}
 	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return ErrFrozen
+	}
+	if c.mergeOnRead {
+		for k, v := range c.mapConfig {
+			if n, ok := conf[k]; ok {
+				conf[k] = ConfigMap{Key: n.Key, Value: c.mergeAt(k, v.Value, n.Value)}
+			} else {
+				conf[k] = v
+			}
+		}
+	}
+	previousConf, previousSecrets := c.mapConfig, c.secretConfig
 	c.mapConfig = conf
-	c.mutex.Unlock()
-	c.collapse()
+	if secrets != nil {
+		c.secretConfig = secrets
+	}
+	if err := c.collapseLocked(); err != nil {
+		c.mapConfig, c.secretConfig = previousConf, previousSecrets
+		return err
+	}
 	return nil
 }
 
 func readFile(filename string, fileType configType) (map[string]any, error) {
-	fileData := make(map[string]any)
 	if d, err := os.Stat(filename); os.IsNotExist(err) {
 		return nil, ErrConfigFileNotFound
 	} else if d.Size() == 0 {
 		return nil, ErrConfigFileEmpty
 	}
+	f, err := openConfigFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := decodeConfig(f, fileType)
+	if err != nil {
+		return nil, newLoadError(filename, fileType, err)
+	}
+	return data, nil
+}
 
+func decodeConfig(in io.Reader, fileType configType) (map[string]any, error) {
+	fileData := make(map[string]any)
 	switch fileType {
 	case ConfigTypeTOML:
-		_, err := toml.DecodeFile(filename, &fileData)
+		_, err := toml.NewDecoder(in).Decode(&fileData)
 		return fileData, err
 	case ConfigTypeYAML:
-		f, err := os.Open(filename)
-		if err != nil {
-			return nil, err
-		}
-		defer f.Close()
-		d := yaml.NewDecoder(f)
-		err = d.Decode(&fileData)
+		err := yaml.NewDecoder(in).Decode(&fileData)
 		return fileData, err
 	case ConfigTypeJSON:
-		f, err := os.Open(filename)
-		if err != nil {
-			return nil, err
-		}
-		defer f.Close()
-		err = json.NewDecoder(f).Decode(&fileData)
+		d := json.NewDecoder(in)
+		d.UseNumber()
+		err := d.Decode(&fileData)
 		return fileData, err
+	case ConfigTypeProperties:
+		return decodeProperties(in)
 	default:
 		return nil, fmt.Errorf("config type %s not supported", fileType)
 	}
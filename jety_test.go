@@ -0,0 +1,170 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFailedLoadKeepsPreviousConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(*ConfigManager)
+		data  string
+	}{
+		{
+			name:  "type coercion",
+			setup: func(c *ConfigManager) { c.RegisterType("port", reflect.Int) },
+			data:  "port: abc\nother: new\n",
+		},
+		{
+			name:  "interpolation cycle",
+			setup: func(c *ConfigManager) { c.EnableInterpolation(true) },
+			data:  "port: ${other}\nother: ${port}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfigManager()
+			if err := c.SetConfigType("yaml"); err != nil {
+				t.Fatal(err)
+			}
+			tt.setup(c)
+			if err := c.ReadConfig(strings.NewReader("port: 80\n")); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.ReadConfig(strings.NewReader(tt.data)); err == nil {
+				t.Fatal("ReadConfig() = nil, want an error")
+			}
+			if got := c.GetInt("port"); got != 80 {
+				t.Errorf("GetInt(port) = %d, want 80", got)
+			}
+			if c.IsSet("other") {
+				t.Error("IsSet(other) = true after a failed load")
+			}
+			if err := c.Set("unrelated", 1); err != nil {
+				t.Fatal(err)
+			}
+			if got := c.GetInt("port"); got != 80 {
+				t.Errorf("after Set, GetInt(port) = %d, want 80", got)
+			}
+		})
+	}
+}
+
+func TestFailedEnvChangeIsReverted(t *testing.T) {
+	t.Setenv("BAD_PORT", "abc")
+
+	c := NewConfigManager()
+	c.RegisterType("port", reflect.Int)
+	if err := c.SetDefault("port", 80); err != nil {
+		t.Fatal(err)
+	}
+	c.SetEnvPrefix("BAD")
+	if got := c.EnvPrefix(); got != "" {
+		t.Errorf("EnvPrefix() = %q, want the previous empty prefix", got)
+	}
+	if got := c.GetInt("port"); got != 80 {
+		t.Errorf("GetInt(port) = %d, want 80", got)
+	}
+	if err := c.BindEnv("port", "BAD_PORT"); !errors.Is(err, ErrWrongType) {
+		t.Errorf("BindEnv() error = %v, want ErrWrongType", err)
+	}
+	if got := c.GetInt("port"); got != 80 {
+		t.Errorf("after BindEnv, GetInt(port) = %d, want 80", got)
+	}
+}
+
+func TestDottedSetIsWrittenOnce(t *testing.T) {
+	c := NewConfigManager()
+	if err := c.SetDefault("db", map[string]any{"host": "a", "port": 5432}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("db.host", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("cache.ttl", 60); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.AllKeys(), []string{"cache.ttl", "db"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AllKeys() = %v, want %v", got, want)
+	}
+	var buf strings.Builder
+	if err := c.WriteConfigTo(&buf, "json"); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "cache": {
+    "ttl": 60
+  },
+  "db": {
+    "host": "b",
+    "port": 5432
+  }
+}
+`
+	if buf.String() != want {
+		t.Errorf("WriteConfigTo() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	// replacing the root keeps the earlier dotted override
+	if err := c.SetDefault("db", map[string]any{"host": "c"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("db.host"); got != "b" {
+		t.Errorf("GetString(db.host) = %q, want b", got)
+	}
+}
+
+func TestFloatRoundTrip(t *testing.T) {
+	floats := map[string]any{
+		"a": 0.1,
+		"b": 0.1 + 0.2,
+		"c": float32(0.1),
+		"d": 1e21,
+		"e": -2.5,
+		"f": 3.0,
+	}
+	for _, configType := range []string{"json", "yaml", "toml"} {
+		t.Run(configType, func(t *testing.T) {
+			c := NewConfigManager()
+			for k, v := range floats {
+				if err := c.Set(k, v); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var buf strings.Builder
+			if err := c.WriteConfigTo(&buf, configType); err != nil {
+				t.Fatal(err)
+			}
+			out := buf.String()
+			if strings.Contains(out, "0.10000000149011612") {
+				t.Errorf("float32 0.1 written with float64 noise:\n%s", out)
+			}
+
+			read := NewConfigManager()
+			if err := read.SetConfigType(configType); err != nil {
+				t.Fatal(err)
+			}
+			if err := read.ReadConfig(strings.NewReader(out)); err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]float64{"a": 0.1, "b": 0.1 + 0.2, "c": 0.1, "d": 1e21, "e": -2.5, "f": 3}
+			for k, w := range want {
+				if got := read.GetFloat64(k); got != w {
+					t.Errorf("GetFloat64(%q) = %v, want %v", k, got, w)
+				}
+			}
+
+			// writing the read config again gives the same output
+			var again strings.Builder
+			if err := read.WriteConfigTo(&again, configType); err != nil {
+				t.Fatal(err)
+			}
+			if again.String() != out {
+				t.Errorf("second write =\n%s\nwant\n%s", again.String(), out)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// The Lookup getters report whether key is set alongside its value, so a
+// zero value can be told apart from a missing key with a single lookup. ok
+// is also false when the value cannot be converted to the requested type.
+
+func (c *ConfigManager) LookupString(key string) (string, bool) {
+	v, ok := c.lookup(key)
+	if !ok {
+		return "", false
+	}
+	if s, ok := v.Value.(string); ok {
+		return s, true
+	}
+	return fmt.Sprintf("%v", v.Value), true
+}
+
+func (c *ConfigManager) LookupInt(key string) (int, bool) {
+	v, ok := c.lookup(key)
+	if !ok {
+		return 0, false
+	}
+	i, err := toInt(c.intInput(v.Value), c.getIntRounding())
+	return i, err == nil
+}
+
+func (c *ConfigManager) LookupInt64(key string) (int64, bool) {
+	v, ok := c.lookup(key)
+	if !ok {
+		return 0, false
+	}
+	i, err := toInt64(c.intInput(v.Value), c.getIntRounding())
+	return i, err == nil
+}
+
+func (c *ConfigManager) LookupBool(key string) (bool, bool) {
+	v, ok := c.lookup(key)
+	if !ok {
+		return false, false
+	}
+	b, err := toBool(v.Value)
+	return b, err == nil
+}
+
+func (c *ConfigManager) LookupDuration(key string) (time.Duration, bool) {
+	v, ok := c.lookup(key)
+	if !ok {
+		return 0, false
+	}
+	d, err := toDuration(v.Value, c.durationUnitFor(key))
+	return d, err == nil
+}
+
+func (c *ConfigManager) LookupStringSlice(key string) ([]string, bool) {
+	v, ok := c.lookup(key)
+	if !ok {
+		return nil, false
+	}
+	return c.toStringSlice(v.Value), true
+}
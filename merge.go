@@ -0,0 +1,261 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SetMergeOnRead makes subsequent reads deep-merge into the existing file
+// config instead of replacing it.
+func (c *ConfigManager) SetMergeOnRead(enable bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.mergeOnRead = enable
+}
+
+// SetDeepMergeMaps makes map values set explicitly or read from a file merge
+// over map defaults instead of replacing them, so a partial override keeps
+// the unspecified default entries.
+func (c *ConfigManager) SetDeepMergeMaps(enable bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	previous := c.deepMergeMaps
+	c.deepMergeMaps = enable
+	if c.collapseLocked() != nil {
+		c.deepMergeMaps = previous
+	}
+}
+
+// overDefault returns v merged over the default (or env) value for lower
+// when deep merging is enabled. The caller must hold the lock.
+func (c *ConfigManager) overDefault(lower string, v ConfigMap) ConfigMap {
+	if !c.deepMergeMaps {
+		return v
+	}
+	base, ok := c.defaultConfig[lower]
+	if !ok {
+		return v
+	}
+	if env, ok := c.envValue(lower); ok {
+		base = c.envOverDefault(base, env)
+	}
+	return ConfigMap{Key: v.Key, Value: c.mergeAt(lower, base.Value, v.Value)}
+}
+
+// SetListMergeKey makes merges of the list at key match entries by their
+// field value: an overlay entry updates the base entry with the same value
+// and entries without a match are appended. Other lists are replaced
+// whole.
+func (c *ConfigManager) SetListMergeKey(key, field string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	if c.listMergeKeys == nil {
+		c.listMergeKeys = make(map[string]string)
+	}
+	c.listMergeKeys[strings.ToLower(key)] = field
+}
+
+// mergeAt merges src over dst for the key lower, honoring the list merge
+// keys. The caller must hold the lock.
+func (c *ConfigManager) mergeAt(lower string, dst, src any) any {
+	return mergeWith(c.listMergeKeys, lower, dst, src)
+}
+
+// mergeValues overlays src onto dst. Maps are merged recursively with keys
+// matched case-insensitively; any other value in src replaces dst.
+func mergeValues(dst, src any) any {
+	return mergeWith(nil, "", dst, src)
+}
+
+func mergeWith(listKeys map[string]string, path string, dst, src any) any {
+	if field, ok := listKeys[path]; ok {
+		if merged, ok := mergeLists(listKeys, path, field, dst, src); ok {
+			return merged
+		}
+	}
+	dm, ok := toStringMap(dst)
+	if !ok {
+		return src
+	}
+	sm, ok := toStringMap(src)
+	if !ok {
+		return src
+	}
+	merged := make(map[string]any, len(dm)+len(sm))
+	keys := make(map[string]string, len(dm))
+	for k, v := range dm {
+		merged[k] = v
+		keys[strings.ToLower(k)] = k
+	}
+	for k, v := range sm {
+		if existing, ok := keys[strings.ToLower(k)]; ok {
+			delete(merged, existing)
+			merged[k] = mergeWith(listKeys, joinKey(path, strings.ToLower(k)), dm[existing], v)
+			continue
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// mergeLists merges the entries of src into dst, matching entries whose
+// field values are equal. It reports false when either value is not a list.
+func mergeLists(listKeys map[string]string, path, field string, dst, src any) (any, bool) {
+	dl, ok := toAnySlice(dst)
+	if !ok {
+		return nil, false
+	}
+	sl, ok := toAnySlice(src)
+	if !ok {
+		return nil, false
+	}
+	merged := dl
+	for _, entry := range sl {
+		id, ok := listEntryID(entry, field)
+		if !ok {
+			merged = append(merged, entry)
+			continue
+		}
+		matched := false
+		for i, existing := range merged {
+			if existingID, ok := listEntryID(existing, field); ok && existingID == id {
+				merged[i] = mergeWith(listKeys, path, existing, entry)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			merged = append(merged, entry)
+		}
+	}
+	return merged, true
+}
+
+func listEntryID(entry any, field string) (string, bool) {
+	m, ok := toStringMap(entry)
+	if !ok {
+		return "", false
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, field) {
+			return fmt.Sprintf("%v", v), true
+		}
+	}
+	return "", false
+}
+
+// toAnySlice copies a slice of any element type, such as the
+// []map[string]any that TOML decodes arrays of tables into, to a []any.
+func toAnySlice(in any) ([]any, bool) {
+	rv := reflect.ValueOf(in)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	out := make([]any, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out, true
+}
+
+// applyNestedOverrides folds dotted keys, such as those passed to Set, and
+// env vars naming nested keys into the maps they belong to, so that map
+// getters and Unmarshal see the effective values. An env var for a nested
+// key is named after its dotted path with the env key replacer applied, or
+// with dots replaced by underscores. The caller must hold the lock.
+func (c *ConfigManager) applyNestedOverrides(ccm map[string]ConfigMap) {
+	for k, v := range ccm {
+		root, rest, ok := strings.Cut(k, ".")
+		if !ok {
+			continue
+		}
+		base, ok := ccm[root]
+		if !ok {
+			continue
+		}
+		if _, ok := toStringMap(base.Value); !ok {
+			continue
+		}
+		ccm[root] = ConfigMap{Key: base.Key, Value: setNested(base.Value, strings.Split(rest, "."), v.Value)}
+		// the value is now reached through its root, so the flat key
+		// would only be written and listed twice
+		delete(ccm, k)
+	}
+	if !c.automaticEnv || len(c.envConfig)+len(c.bareEnv) == 0 {
+		return
+	}
+	for k, v := range ccm {
+		if strings.Contains(k, ".") {
+			continue
+		}
+		if _, ok := toStringMap(v.Value); !ok {
+			continue
+		}
+		if val, changed := c.envOverNested(k, v.Value); changed {
+			ccm[k] = ConfigMap{Key: v.Key, Value: val}
+		}
+	}
+}
+
+// envOverNested replaces the leaves below path that have env values,
+// copying only the maps that change. The caller must hold the lock.
+func (c *ConfigManager) envOverNested(path string, val any) (any, bool) {
+	m, ok := toStringMap(val)
+	if !ok {
+		if _, ok := c.envBindings[path]; ok {
+			// bound keys are resolved from their own env vars in collapse
+			return val, false
+		}
+		for _, name := range []string{c.envKey(path), strings.ReplaceAll(path, ".", "_")} {
+			if env, ok := c.envValue(name); ok {
+				return c.envOverDefault(ConfigMap{Key: path, Value: val}, env).Value, true
+			}
+		}
+		return val, false
+	}
+	var out map[string]any
+	for k, v := range m {
+		nv, changed := c.envOverNested(path+"."+strings.ToLower(k), v)
+		if !changed {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]any, len(m))
+			for k, v := range m {
+				out[k] = v
+			}
+		}
+		out[k] = nv
+	}
+	if out == nil {
+		return val, false
+	}
+	return out, true
+}
+
+// setNested returns a copy of the map val with the value at the path parts
+// replaced by leaf, creating intermediate maps as needed.
+func setNested(val any, parts []string, leaf any) any {
+	m, _ := toStringMap(val)
+	out := make(map[string]any, len(m)+1)
+	key := parts[0]
+	for k, v := range m {
+		out[k] = v
+		if strings.EqualFold(k, parts[0]) {
+			key = k
+		}
+	}
+	if len(parts) == 1 {
+		out[key] = leaf
+	} else {
+		out[key] = setNested(out[key], parts[1:], leaf)
+	}
+	return out
+}
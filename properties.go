@@ -0,0 +1,192 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// decodeProperties parses a Java .properties document into a map of string
+// values. Dotted keys such as db.host are nested under their root, as they
+// would be in the other formats.
+func decodeProperties(in io.Reader) (map[string]any, error) {
+	data := make(map[string]any)
+	scanner := bufio.NewScanner(in)
+	var logical strings.Builder
+	continuing := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if continuing {
+			line = strings.TrimLeft(line, " \t\f")
+		} else {
+			trimmed := strings.TrimLeft(line, " \t\f")
+			if trimmed == "" || trimmed[0] == '#' || trimmed[0] == '!' {
+				continue
+			}
+			line = trimmed
+		}
+		if trailingBackslashes(line)%2 == 1 {
+			logical.WriteString(line[:len(line)-1])
+			continuing = true
+			continue
+		}
+		logical.WriteString(line)
+		continuing = false
+		key, value, err := splitProperty(logical.String())
+		logical.Reset()
+		if err != nil {
+			return nil, err
+		}
+		data[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if continuing {
+		key, value, err := splitProperty(logical.String())
+		if err != nil {
+			return nil, err
+		}
+		data[key] = value
+	}
+	nestDottedKeys(data)
+	return data, nil
+}
+
+func trailingBackslashes(s string) int {
+	n := 0
+	for i := len(s) - 1; i >= 0 && s[i] == '\\'; i-- {
+		n++
+	}
+	return n
+}
+
+func splitProperty(line string) (string, string, error) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+	key, rest := line[:end], line[end:]
+	rest = strings.TrimLeft(rest, " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	k, err := unescapeProperty(key)
+	if err != nil {
+		return "", "", err
+	}
+	v, err := unescapeProperty(rest)
+	if err != nil {
+		return "", "", err
+	}
+	return k, v, nil
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("invalid unicode escape in %q", s)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape in %q", s)
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// encodeProperties writes config as sorted key=value lines. Nested maps are
+// flattened into dotted keys.
+func encodeProperties(w io.Writer, config map[string]any) error {
+	flat := make(map[string]any)
+	flattenInto(flat, "", config)
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	bw := bufio.NewWriter(w)
+	for _, k := range keys {
+		v := flat[k]
+		var value string
+		if s, ok := v.(string); ok {
+			value = s
+		} else {
+			value = fmt.Sprintf("%v", v)
+		}
+		fmt.Fprintf(bw, "%s=%s\n", escapeProperty(k, true), escapeProperty(value, false))
+	}
+	return bw.Flush()
+}
+
+func flattenInto(dst map[string]any, prefix string, src map[string]any) {
+	for k, v := range src {
+		if m, ok := toStringMap(v); ok {
+			flattenInto(dst, joinKey(prefix, k), m)
+			continue
+		}
+		dst[joinKey(prefix, k)] = v
+	}
+}
+
+func escapeProperty(s string, isKey bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\f':
+			b.WriteString(`\f`)
+		case r == '=' || r == ':' || r == '#' || r == '!':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == ' ' && (isKey || i == 0):
+			b.WriteString(`\ `)
+		case r > 0x7e && r <= 0xffff:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPropertiesNestDottedKeys(t *testing.T) {
+	c := NewConfigManager()
+	if err := c.SetConfigType("properties"); err != nil {
+		t.Fatal(err)
+	}
+	data := "name=app\ndb.host=localhost\ndb.port=5432\ndb.pool.size=4\n"
+	if err := c.ReadConfig(strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"host": "localhost",
+		"port": "5432",
+		"pool": map[string]any{"size": "4"},
+	}
+	if got := c.GetStringMap("db"); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetStringMap(db) = %v, want %v", got, want)
+	}
+	if got := c.GetInt("db.pool.size"); got != 4 {
+		t.Errorf("GetInt(db.pool.size) = %d, want 4", got)
+	}
+
+	var buf strings.Builder
+	if err := c.WriteConfigTo(&buf, "properties"); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "db.host=localhost\ndb.pool.size=4\ndb.port=5432\nname=app\n" {
+		t.Errorf("WriteConfigTo() =\n%s", got)
+	}
+}
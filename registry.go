@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+type registeredGetter struct {
+	name    string
+	convert func(any) (any, error)
+}
+
+var (
+	getterMutex       sync.RWMutex
+	registeredGetters = make(map[reflect.Type]registeredGetter)
+)
+
+// RegisterGetter registers convert as the conversion from stored config
+// values to T, for use by GetRegistered. Registering a type again replaces
+// its converter.
+func RegisterGetter[T any](name string, convert func(any) (T, error)) {
+	getterMutex.Lock()
+	defer getterMutex.Unlock()
+	registeredGetters[reflect.TypeOf((*T)(nil)).Elem()] = registeredGetter{
+		name: name,
+		convert: func(in any) (any, error) {
+			return convert(in)
+		},
+	}
+}
+
+// GetRegistered converts the value at key in the default config manager to
+// T using the converter registered for T.
+func GetRegistered[T any](key string) (T, error) {
+	return GetRegisteredFrom[T](defaultConfigManager, key)
+}
+
+// GetRegisteredFrom converts the value at key in c to T using the converter
+// registered for T.
+func GetRegisteredFrom[T any](c *ConfigManager, key string) (T, error) {
+	var zero T
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	getterMutex.RLock()
+	g, ok := registeredGetters[t]
+	getterMutex.RUnlock()
+	if !ok {
+		return zero, fmt.Errorf("no getter registered for type %s", t)
+	}
+	v, ok := c.lookup(key)
+	if !ok {
+		return zero, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	out, err := g.convert(v.Value)
+	if err != nil {
+		return zero, fmt.Errorf("key %s: %s: %w", key, g.name, err)
+	}
+	typed, ok := out.(T)
+	if !ok {
+		return zero, fmt.Errorf("key %s: %w: %s returned %T, not %s", key, ErrWrongType, g.name, out, t)
+	}
+	return typed, nil
+}
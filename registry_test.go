@@ -0,0 +1,19 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestGetRegisteredNilInterface(t *testing.T) {
+	RegisterGetter("stringer", func(any) (fmt.Stringer, error) { return nil, nil })
+
+	c := NewConfigManager()
+	if err := c.Set("name", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetRegisteredFrom[fmt.Stringer](c, "name"); !errors.Is(err, ErrWrongType) {
+		t.Errorf("GetRegisteredFrom() error = %v, want ErrWrongType", err)
+	}
+}
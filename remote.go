@@ -0,0 +1,103 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+type (
+	RemoteOption func(*remoteOptions)
+
+	remoteOptions struct {
+		client  *http.Client
+		retries int
+		backoff time.Duration
+	}
+
+	remoteStatusError struct {
+		url        string
+		statusCode int
+	}
+)
+
+func (e *remoteStatusError) Error() string {
+	return fmt.Sprintf("fetching remote config %s: unexpected status %d", e.url, e.statusCode)
+}
+
+func WithHTTPClient(client *http.Client) RemoteOption {
+	return func(o *remoteOptions) {
+		o.client = client
+	}
+}
+
+// WithRetry retries failed fetches up to n times, doubling the wait after
+// each attempt starting from base. Network errors and 5xx responses are
+// retried; 4xx responses are not.
+func WithRetry(n int, base time.Duration) RemoteOption {
+	return func(o *remoteOptions) {
+		o.retries = n
+		o.backoff = base
+	}
+}
+
+func (c *ConfigManager) ReadRemoteConfig(ctx context.Context, url string, opts ...RemoteOption) error {
+	o := remoteOptions{client: http.DefaultClient}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var (
+		data []byte
+		err  error
+	)
+	backoff := o.backoff
+	for attempt := 0; ; attempt++ {
+		data, err = fetchRemote(ctx, o.client, url)
+		if err == nil || attempt >= o.retries || !retryable(err) || ctx.Err() != nil {
+			break
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			break
+		}
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+		backoff *= 2
+	}
+	if err != nil {
+		return err
+	}
+	return c.ReadConfig(bytes.NewReader(data))
+}
+
+func fetchRemote(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &remoteStatusError{url: url, statusCode: resp.StatusCode}
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func retryable(err error) bool {
+	var statusErr *remoteStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.statusCode >= 500
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// SetBestEffortLoad makes reads that fail to decode keep the top-level YAML
+// keys and TOML tables that do decode instead of failing. The decode error
+// is sent to the Warnings channel. Schema validation is skipped for a
+// partially loaded config.
+func (c *ConfigManager) SetBestEffortLoad(enable bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.bestEffort = enable
+	if enable && c.warnings == nil {
+		c.warnings = make(chan error, 16)
+	}
+}
+
+// Warnings returns the channel that receives decode errors recovered from
+// in best-effort mode. It is nil until SetBestEffortLoad(true) is called.
+// Warnings are dropped while the channel is full.
+func (c *ConfigManager) Warnings() <-chan error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.warnings
+}
+
+// salvage decodes the sections of data that parse on their own when
+// best-effort loading is enabled, reporting decodeErr as a warning if
+// anything was recovered.
+func (c *ConfigManager) salvage(data []byte, ct configType, decodeErr error) (map[string]any, bool) {
+	c.mutex.RLock()
+	enabled, warnings := c.bestEffort, c.warnings
+	c.mutex.RUnlock()
+	if !enabled {
+		return nil, false
+	}
+	var sections []string
+	switch ct {
+	case ConfigTypeYAML:
+		sections = splitSections(string(data), func(line string) bool {
+			return line[0] != ' ' && line[0] != '\t' && line[0] != '#' && line[0] != '-'
+		})
+	case ConfigTypeTOML:
+		sections = splitSections(string(data), func(line string) bool {
+			return line[0] == '['
+		})
+	default:
+		return nil, false
+	}
+	var salvaged map[string]any
+	for _, section := range sections {
+		m, err := decodeConfig(strings.NewReader(section), ct)
+		if err != nil || len(m) == 0 {
+			continue
+		}
+		if salvaged == nil {
+			salvaged = m
+			continue
+		}
+		salvaged = mergeValues(salvaged, m).(map[string]any)
+	}
+	if salvaged == nil {
+		return nil, false
+	}
+	select {
+	case warnings <- decodeErr:
+	default:
+	}
+	return salvaged, true
+}
+
+func (c *ConfigManager) salvageFile(filename string, ct configType, decodeErr error) (map[string]any, bool) {
+	f, err := openConfigFile(filename)
+	if err != nil {
+		return nil, false
+	}
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return nil, false
+	}
+	return c.salvage(data, ct, decodeErr)
+}
+
+// splitSections splits doc into chunks that each start at a line for which
+// starts returns true.
+func splitSections(doc string, starts func(line string) bool) []string {
+	var (
+		sections []string
+		current  bytes.Buffer
+	)
+	for _, line := range strings.SplitAfter(doc, "\n") {
+		if strings.TrimSpace(line) != "" && starts(line) && current.Len() > 0 {
+			sections = append(sections, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		sections = append(sections, current.String())
+	}
+	return sections
+}
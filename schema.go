@@ -0,0 +1,34 @@
+package config
+
+import (
+	"bytes"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RegisterSchema enables strict decoding of YAML configs: a file containing
+// a field that does not exist in schema fails to load. schema is a struct
+// or a pointer to one; nil disables strict decoding.
+func (c *ConfigManager) RegisterSchema(schema any) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if schema == nil {
+		c.schema = nil
+		return
+	}
+	t := reflect.TypeOf(schema)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	c.schema = t
+}
+
+func validateSchema(data []byte, ct configType, schema reflect.Type) error {
+	if schema == nil || ct != ConfigTypeYAML {
+		return nil
+	}
+	d := yaml.NewDecoder(bytes.NewReader(data))
+	d.KnownFields(true)
+	return d.Decode(reflect.New(schema).Interface())
+}
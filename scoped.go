@@ -0,0 +1,89 @@
+package config
+
+import "time"
+
+// ScopedConfig reads keys relative to a prefix of its parent ConfigManager.
+// Reads go through to the parent, so later changes to the parent are
+// visible.
+type ScopedConfig struct {
+	parent *ConfigManager
+	prefix string
+}
+
+// WithPrefix returns a view whose getters resolve key as prefix.key.
+func (c *ConfigManager) WithPrefix(prefix string) *ScopedConfig {
+	return &ScopedConfig{parent: c, prefix: prefix}
+}
+
+// WithPrefix returns a view nested below the current prefix.
+func (s *ScopedConfig) WithPrefix(prefix string) *ScopedConfig {
+	return &ScopedConfig{parent: s.parent, prefix: s.key(prefix)}
+}
+
+func (s *ScopedConfig) Prefix() string {
+	return s.prefix
+}
+
+func (s *ScopedConfig) key(key string) string {
+	return joinKey(s.prefix, key)
+}
+
+func (s *ScopedConfig) Get(key string) any {
+	return s.parent.Get(s.key(key))
+}
+
+func (s *ScopedConfig) IsSet(key string) bool {
+	return s.parent.IsSet(s.key(key))
+}
+
+func (s *ScopedConfig) GetString(key string) string {
+	return s.parent.GetString(s.key(key))
+}
+
+func (s *ScopedConfig) GetBool(key string) bool {
+	return s.parent.GetBool(s.key(key))
+}
+
+func (s *ScopedConfig) GetInt(key string) int {
+	return s.parent.GetInt(s.key(key))
+}
+
+func (s *ScopedConfig) GetInt64(key string) int64 {
+	return s.parent.GetInt64(s.key(key))
+}
+
+func (s *ScopedConfig) GetUint64(key string) uint64 {
+	return s.parent.GetUint64(s.key(key))
+}
+
+func (s *ScopedConfig) GetFloat64(key string) float64 {
+	return s.parent.GetFloat64(s.key(key))
+}
+
+func (s *ScopedConfig) GetDuration(key string) time.Duration {
+	return s.parent.GetDuration(s.key(key))
+}
+
+func (s *ScopedConfig) GetTime(key string) time.Time {
+	return s.parent.GetTime(s.key(key))
+}
+
+func (s *ScopedConfig) GetStringSlice(key string) []string {
+	return s.parent.GetStringSlice(s.key(key))
+}
+
+func (s *ScopedConfig) GetIntSlice(key string) []int {
+	return s.parent.GetIntSlice(s.key(key))
+}
+
+func (s *ScopedConfig) GetStringMap(key string) map[string]any {
+	return s.parent.GetStringMap(s.key(key))
+}
+
+func (s *ScopedConfig) GetStringMapString(key string) map[string]string {
+	return s.parent.GetStringMapString(s.key(key))
+}
+
+func (s *ScopedConfig) UnmarshalKey(key string, out any) error {
+	return s.parent.UnmarshalKey(s.key(key), out)
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// reader lists the getters shared by ScopedConfig and ConfigView.
+type reader interface {
+	Get(key string) any
+	IsSet(key string) bool
+	GetString(key string) string
+	GetBool(key string) bool
+	GetInt(key string) int
+	GetInt64(key string) int64
+	GetUint64(key string) uint64
+	GetFloat64(key string) float64
+	GetDuration(key string) time.Duration
+	GetTime(key string) time.Time
+	GetStringSlice(key string) []string
+	GetIntSlice(key string) []int
+	GetStringMap(key string) map[string]any
+	GetStringMapString(key string) map[string]string
+	UnmarshalKey(key string, out any) error
+}
+
+var (
+	_ reader = (*ScopedConfig)(nil)
+	_ reader = ConfigView{}
+)
+
+func TestScopedMatchesSnapshot(t *testing.T) {
+	c := NewConfigManager()
+	if err := c.SetConfigType("yaml"); err != nil {
+		t.Fatal(err)
+	}
+	data := `
+app:
+  id: 18446744073709551615
+  started: 2024-01-02T03:04:05Z
+  ports: [80, 443]
+  labels: {env: prod}
+  db: {host: h, port: 5432}
+`
+	if err := c.ReadConfig(strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	scoped := c.WithPrefix("app")
+	view := c.Snapshot()
+	for _, r := range []struct {
+		name   string
+		r      reader
+		prefix string
+	}{{"scoped", scoped, ""}, {"snapshot", view, "app."}} {
+		t.Run(r.name, func(t *testing.T) {
+			if got := r.r.GetUint64(r.prefix + "id"); got != 18446744073709551615 {
+				t.Errorf("GetUint64(id) = %d", got)
+			}
+			if got := r.r.GetTime(r.prefix + "started"); !got.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+				t.Errorf("GetTime(started) = %v", got)
+			}
+			if got, want := r.r.GetIntSlice(r.prefix+"ports"), []int{80, 443}; !reflect.DeepEqual(got, want) {
+				t.Errorf("GetIntSlice(ports) = %v, want %v", got, want)
+			}
+			if got, want := r.r.GetStringMapString(r.prefix+"labels"), map[string]string{"env": "prod"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("GetStringMapString(labels) = %v, want %v", got, want)
+			}
+			var db struct {
+				Host string
+				Port int
+			}
+			if err := r.r.UnmarshalKey(r.prefix+"db", &db); err != nil {
+				t.Fatal(err)
+			}
+			if db.Host != "h" || db.Port != 5432 {
+				t.Errorf("UnmarshalKey(db) = %+v", db)
+			}
+		})
+	}
+}
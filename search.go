@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var searchExtensions = []string{"yaml", "yml", "toml", "json", "properties"}
+
+// AddStandardPaths extends config discovery beyond the config dir and the
+// working directory to $HOME/.config/<name> and /etc/<name>.
+func (c *ConfigManager) AddStandardPaths() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.standardPaths = true
+}
+
+// SearchedPaths returns the candidate files checked by the last
+// ReadInConfig, in the order they were tried.
+func (c *ConfigManager) SearchedPaths() []string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return append([]string(nil), c.searchedPaths...)
+}
+
+func (c *ConfigManager) searchDirs() []string {
+	var dirs []string
+	if c.configPath != "" {
+		dirs = append(dirs, c.configPath)
+	}
+	dirs = append(dirs, ".")
+	if c.standardPaths {
+		if home, err := os.UserHomeDir(); err == nil {
+			dirs = append(dirs, filepath.Join(home, ".config", c.configName))
+		}
+		dirs = append(dirs, filepath.Join("/etc", c.configName))
+	}
+	return dirs
+}
+
+// findConfigFile resolves the config file from the config name when no file
+// was set explicitly, and returns the type to decode it as. A type inferred
+// from the file's extension applies to this read only, so a later file with
+// another extension is not decoded with it. The caller must hold the write
+// lock.
+func (c *ConfigManager) findConfigFile() (configType, error) {
+	c.searchedPaths = nil
+	if c.configFileUsed != "" {
+		c.searchedPaths = append(c.searchedPaths, c.configFileUsed)
+		if c.configType != "" {
+			return c.configType, nil
+		}
+		ct, err := inferConfigType(c.configFileUsed)
+		if err != nil {
+			return "", fmt.Errorf("unable to determine config type from path %s: %w", c.configFileUsed, ErrConfigTypeUnset)
+		}
+		return ct, nil
+	}
+	if c.configName == "" {
+		return "", fmt.Errorf("%w: %w", ErrConfigFileNotFound, ErrNoConfigFile)
+	}
+	for _, dir := range c.searchDirs() {
+		for _, ext := range searchExtensions {
+			path := filepath.Join(dir, c.configName+"."+ext)
+			c.searchedPaths = append(c.searchedPaths, path)
+			if info, err := os.Stat(path); err != nil || info.IsDir() {
+				continue
+			}
+			c.configFileUsed = path
+			if c.configType != "" {
+				return c.configType, nil
+			}
+			if ext == "yaml" || ext == "yml" {
+				return ConfigTypeYAML, nil
+			}
+			return configType(ext), nil
+		}
+	}
+	return "", ErrConfigFileNotFound
+}
@@ -0,0 +1,22 @@
+package config
+
+import "testing"
+
+func TestReadInConfigInfersTypePerFile(t *testing.T) {
+	dir := t.TempDir()
+	c := NewConfigManager()
+	c.SetConfigFile(writeFile(t, dir, "a.yaml", "name: yaml\n"))
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("name"); got != "yaml" {
+		t.Errorf("GetString(name) = %q, want yaml", got)
+	}
+	c.SetConfigFile(writeFile(t, dir, "b.toml", "name = \"toml\"\n"))
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetString("name"); got != "toml" {
+		t.Errorf("GetString(name) = %q, want toml", got)
+	}
+}
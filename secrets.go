@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// SetSecretsFile names a file that ReadInConfig merges over the main config.
+// Its keys take precedence and stay readable through getters, but are never
+// written back by WriteConfig and are redacted from EnvSettings.
+func (c *ConfigManager) SetSecretsFile(path string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.secretsFile = path
+}
+
+// readSecrets loads the secrets file, inferring its type from the extension
+// and falling back to the main config type.
+func readSecrets(filename string, fallback configType) (map[string]ConfigMap, error) {
+	ct, err := inferConfigType(filename)
+	if err != nil {
+		ct = fallback
+	}
+	data, err := readFile(filename, ct)
+	if err != nil {
+		return nil, fmt.Errorf("reading secrets file %s: %w", filename, err)
+	}
+	secrets := make(map[string]ConfigMap, len(data))
+	for k, v := range data {
+		secrets[strings.ToLower(k)] = ConfigMap{Key: k, Value: v}
+	}
+	return secrets, nil
+}
+
+// withoutSecrets returns config with every key from the secrets file
+// removed. Maps that only partly consist of secrets keep their other
+// entries. The caller must hold the lock.
+func (c *ConfigManager) withoutSecrets(config map[string]ConfigMap) map[string]ConfigMap {
+	if len(c.secretConfig) == 0 {
+		return config
+	}
+	out := make(map[string]ConfigMap, len(config))
+	for k, v := range config {
+		out[k] = v
+	}
+	for k, s := range c.secretConfig {
+		v, ok := out[k]
+		if !ok {
+			continue
+		}
+		if stripped, keep := stripSecrets(v.Value, s.Value); keep {
+			out[k] = ConfigMap{Key: v.Key, Value: stripped}
+		} else {
+			delete(out, k)
+		}
+	}
+	return out
+}
+
+func stripSecrets(v, secret any) (any, bool) {
+	sm, ok := toStringMap(secret)
+	if !ok {
+		return nil, false
+	}
+	vm, ok := toStringMap(v)
+	if !ok {
+		return nil, false
+	}
+	secretKeys := make(map[string]string, len(sm))
+	for k := range sm {
+		secretKeys[strings.ToLower(k)] = k
+	}
+	out := make(map[string]any, len(vm))
+	for k, val := range vm {
+		sk, ok := secretKeys[strings.ToLower(k)]
+		if !ok {
+			out[k] = val
+			continue
+		}
+		if stripped, keep := stripSecrets(val, sm[sk]); keep {
+			out[k] = stripped
+		}
+	}
+	if len(out) == 0 {
+		return nil, false
+	}
+	return out, true
+}
+
+// secretEnvKeys returns the env names of every key in the secrets file, both
+// as produced by envKey and with dots replaced by underscores, the two forms
+// that override nested keys. The caller must hold the lock.
+func (c *ConfigManager) secretEnvKeys() map[string]bool {
+	if len(c.secretConfig) == 0 {
+		return nil
+	}
+	settings := make(map[string]any, len(c.secretConfig))
+	for k, v := range c.secretConfig {
+		settings[k] = v.Value
+	}
+	flat := make(map[string]any)
+	flattenInto(flat, "", settings)
+	keys := make(map[string]bool, 2*(len(flat)+len(settings)))
+	for k := range settings {
+		keys[c.envKey(k)] = true
+	}
+	for k := range flat {
+		lower := strings.ToLower(k)
+		keys[c.envKey(lower)] = true
+		keys[strings.ReplaceAll(lower, ".", "_")] = true
+	}
+	return keys
+}
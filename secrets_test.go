@@ -0,0 +1,52 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, data string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadInConfigFrozenKeepsSecrets(t *testing.T) {
+	dir := t.TempDir()
+	c := NewConfigManager()
+	c.SetConfigFile(writeFile(t, dir, "config.yaml", "host: h\n"))
+	secrets := writeFile(t, dir, "secrets.yaml", "db:\n  password: OLD\n")
+	c.SetSecretsFile(secrets)
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	c.Freeze()
+	writeFile(t, dir, "secrets.yaml", "db:\n  password: NEW\n")
+	if err := c.ReadInConfig(); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("ReadInConfig() error = %v, want ErrFrozen", err)
+	}
+	if got := c.GetString("db.password"); got != "OLD" {
+		t.Errorf("GetString(db.password) = %q, want OLD", got)
+	}
+}
+
+func TestEnvSettingsRedactsNestedSecrets(t *testing.T) {
+	t.Setenv("APP_DB_PASSWORD", "leak")
+
+	dir := t.TempDir()
+	c := NewConfigManager()
+	c.SetEnvPrefix("APP")
+	c.SetConfigFile(writeFile(t, dir, "config.yaml", "host: h\n"))
+	c.SetSecretsFile(writeFile(t, dir, "secrets.yaml", "db:\n  password: s3cret\n"))
+	if err := c.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.EnvSettings()["APP_DB_PASSWORD"]; got != redacted {
+		t.Errorf("EnvSettings()[APP_DB_PASSWORD] = %q, want %q", got, redacted)
+	}
+}
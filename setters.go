@@ -1,46 +1,298 @@
 package config
 
 import (
+	"fmt"
+	"reflect"
 	"strings"
 )
 
-func (c *ConfigManager) SetBool(key string, value bool) {
+// ensureMaps allocates the config maps of a ConfigManager that was not
+// created with NewConfigManager. The caller must hold the write lock.
+func (c *ConfigManager) ensureMaps() {
+	if c.mapConfig == nil {
+		c.mapConfig = make(map[string]ConfigMap)
+	}
+	if c.defaultConfig == nil {
+		c.defaultConfig = make(map[string]ConfigMap)
+	}
+	if c.envConfig == nil {
+		c.envConfig = make(map[string]ConfigMap)
+	}
+	if c.combinedConfig == nil {
+		c.combinedConfig = make(map[string]ConfigMap)
+	}
+}
+
+func (c *ConfigManager) SetBool(key string, value bool) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return ErrFrozen
+	}
+	c.ensureMaps()
+	lower := strings.ToLower(key)
+	old, hadOld := c.find(lower)
+	defer c.recordChange(lower, old, hadOld)
+	return c.setLayer(c.mapConfig, lower, ConfigMap{Key: key, Value: value})
+}
+
+func (c *ConfigManager) SetString(key string, value string) error {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
+	if c.frozen {
+		return ErrFrozen
+	}
+	c.ensureMaps()
 	lower := strings.ToLower(key)
-	c.mapConfig[lower] = ConfigMap{Key: key, Value: value}
-	c.combinedConfig[lower] = ConfigMap{Key: key, Value: value}
+	old, hadOld := c.find(lower)
+	defer c.recordChange(lower, old, hadOld)
+	return c.setLayer(c.mapConfig, lower, ConfigMap{Key: key, Value: value})
 }
 
-func (c *ConfigManager) SetString(key string, value string) {
+func (c *ConfigManager) Set(key string, value any) error {
+	if err := checkSerializable(value); err != nil {
+		return fmt.Errorf("key %s: %w", key, err)
+	}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
+	if c.frozen {
+		return ErrFrozen
+	}
+	c.ensureMaps()
 	lower := strings.ToLower(key)
-	c.mapConfig[lower] = ConfigMap{Key: key, Value: value}
-	c.combinedConfig[lower] = ConfigMap{Key: key, Value: value}
+	old, hadOld := c.find(lower)
+	defer c.recordChange(lower, old, hadOld)
+	// secrets and command-line args still outrank Set
+	return c.setLayer(c.mapConfig, lower, ConfigMap{Key: key, Value: value})
+}
+
+// setLayer stores v at lower in layer and rebuilds the effective config,
+// so that interpolated values and registered types stay consistent. If the
+// rebuild fails, the layer's previous entry is restored. The caller must
+// hold the write lock.
+func (c *ConfigManager) setLayer(layer map[string]ConfigMap, lower string, v ConfigMap) error {
+	previous, had := layer[lower]
+	layer[lower] = v
+	if err := c.rebuild(); err != nil {
+		if had {
+			layer[lower] = previous
+		} else {
+			delete(layer, lower)
+		}
+		return err
+	}
+	return nil
 }
 
-func (c *ConfigManager) Set(key string, value any) {
+// Append adds values to the slice at key, creating the slice if key is not
+// set. The read and the write happen under one lock, so concurrent appends
+// are not lost.
+func (c *ConfigManager) Append(key string, values ...any) error {
+	for _, v := range values {
+		if err := checkSerializable(v); err != nil {
+			return fmt.Errorf("key %s: %w", key, err)
+		}
+	}
+	c.ensureCollapsed()
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
+	if c.frozen {
+		return ErrFrozen
+	}
+	c.ensureMaps()
 	lower := strings.ToLower(key)
-	c.mapConfig[lower] = ConfigMap{Key: key, Value: value}
-	c.combinedConfig[lower] = ConfigMap{Key: key, Value: value}
+	var list []any
+	if existing, ok := c.find(lower); ok && existing.Value != nil {
+		rv := reflect.ValueOf(existing.Value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return fmt.Errorf("key %s: %w: cannot append to %T", key, ErrWrongType, existing.Value)
+		}
+		list = make([]any, rv.Len(), rv.Len()+len(values))
+		for i := range list {
+			list[i] = rv.Index(i).Interface()
+		}
+	}
+	for _, v := range values {
+		list = append(list, normalizeMapKeys(v))
+	}
+	old, hadOld := c.find(lower)
+	defer c.recordChange(lower, old, hadOld)
+	if hadOld {
+		key = old.Key
+	}
+	return c.setLayer(c.mapConfig, lower, ConfigMap{Key: key, Value: list})
 }
 
-func (c *ConfigManager) SetDefault(key string, value any) {
+func (c *ConfigManager) SetDefault(key string, value any) error {
+	if err := checkSerializable(value); err != nil {
+		return fmt.Errorf("key %s: %w", key, err)
+	}
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
+	if c.frozen {
+		return ErrFrozen
+	}
+	c.ensureMaps()
 	lower := strings.ToLower(key)
-	c.defaultConfig[lower] = ConfigMap{Key: key, Value: value}
-	if _, ok := c.mapConfig[lower]; !ok {
-		if envVal, ok := c.envConfig[lower]; ok {
-			c.mapConfig[lower] = envVal
-			c.combinedConfig[lower] = envVal
-		} else {
-			c.combinedConfig[lower] = ConfigMap{Key: key, Value: value}
+	old, hadOld := c.find(lower)
+	defer c.recordChange(lower, old, hadOld)
+	return c.setLayer(c.defaultConfig, lower, ConfigMap{Key: key, Value: value})
+}
+
+func (c *ConfigManager) SetDefaultsFromStruct(s any) error {
+	rv := reflect.ValueOf(s)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("defaults must be a struct, got nil %T", s)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("defaults must be a struct, got %T", s)
+	}
+	for k, v := range structToMap(rv) {
+		if err := c.SetDefault(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BindStruct sets defaults from the `default` tags of the struct s, parsing
+// each tag into its field's type. Nested structs become nested map defaults,
+// and slice tags are split on commas.
+func (c *ConfigManager) BindStruct(s any) error {
+	t := reflect.TypeOf(s)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("BindStruct requires a struct, got %T", s)
+	}
+	// parsing defaults is not a read of the config, so skip usage tracking
+	d := c.newDecoder(false)
+	d.used = nil
+	defaults, err := tagDefaults(d, "", t)
+	if err != nil {
+		return err
+	}
+	for k, v := range defaults {
+		if err := c.SetDefault(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func tagDefaults(d *decoder, path string, t reflect.Type) (map[string]any, error) {
+	m := make(map[string]any)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if embedded(f) {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			promoted, err := tagDefaults(d, path, ft)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range promoted {
+				m[k] = v
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		tag, hasTag := f.Tag.Lookup("default")
+		if !hasTag {
+			if ft.Kind() == reflect.Struct && ft != timeType {
+				nested, err := tagDefaults(d, joinKey(path, name), ft)
+				if err != nil {
+					return nil, err
+				}
+				if len(nested) > 0 {
+					m[name] = nested
+				}
+			}
+			continue
+		}
+		var in any = tag
+		if ft.Kind() == reflect.Slice {
+			parts := strings.Split(tag, ",")
+			elems := make([]any, len(parts))
+			for i, p := range parts {
+				elems[i] = strings.TrimSpace(p)
+			}
+			in = elems
+		}
+		out := reflect.New(ft).Elem()
+		if err := d.decode(joinKey(path, name), in, out); err != nil {
+			return nil, fmt.Errorf("invalid default: %w", err)
+		}
+		if out.Kind() == reflect.Slice {
+			// store slices the way decoded config files do
+			elems := make([]any, out.Len())
+			for i := range elems {
+				elems[i] = out.Index(i).Interface()
+			}
+			m[name] = elems
+			continue
+		}
+		m[name] = out.Interface()
+	}
+	return m, nil
+}
+
+func structToMap(rv reflect.Value) map[string]any {
+	m := make(map[string]any)
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if embedded(f) {
+			fv := rv.Field(i)
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			for k, v := range structToMap(fv) {
+				m[k] = v
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+		fv := rv.Field(i)
+		for fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				break
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Pointer {
+			continue
+		}
+		if fv.Kind() == reflect.Struct && fv.Type() != timeType {
+			m[name] = structToMap(fv)
+			continue
 		}
-	} else {
-		c.combinedConfig[lower] = c.mapConfig[lower]
+		m[name] = fv.Interface()
 	}
+	return m
 }
@@ -0,0 +1,86 @@
+package config
+
+import "time"
+
+// ConfigView is an immutable copy of a ConfigManager's config. All of its
+// getters read from the same generation of the config, regardless of later
+// sets or reloads of the manager it was taken from.
+type ConfigView struct {
+	cm *ConfigManager
+}
+
+// Snapshot returns a consistent, read-only view of the current config.
+func (c *ConfigManager) Snapshot() ConfigView {
+	c.ensureCollapsed()
+	cm := c.Clone()
+	cm.mutex.Lock()
+	// bound env vars are otherwise read at lookup time, so fix their
+	// current values in the copy
+	if len(cm.envBindings) > 0 {
+		_ = cm.rebuild()
+		cm.envBindings = nil
+	}
+	cm.mutex.Unlock()
+	cm.Freeze()
+	return ConfigView{cm: cm}
+}
+
+func (v ConfigView) Get(key string) any {
+	return v.cm.Get(key)
+}
+
+func (v ConfigView) IsSet(key string) bool {
+	return v.cm.IsSet(key)
+}
+
+func (v ConfigView) GetString(key string) string {
+	return v.cm.GetString(key)
+}
+
+func (v ConfigView) GetBool(key string) bool {
+	return v.cm.GetBool(key)
+}
+
+func (v ConfigView) GetInt(key string) int {
+	return v.cm.GetInt(key)
+}
+
+func (v ConfigView) GetInt64(key string) int64 {
+	return v.cm.GetInt64(key)
+}
+
+func (v ConfigView) GetUint64(key string) uint64 {
+	return v.cm.GetUint64(key)
+}
+
+func (v ConfigView) GetFloat64(key string) float64 {
+	return v.cm.GetFloat64(key)
+}
+
+func (v ConfigView) GetDuration(key string) time.Duration {
+	return v.cm.GetDuration(key)
+}
+
+func (v ConfigView) GetTime(key string) time.Time {
+	return v.cm.GetTime(key)
+}
+
+func (v ConfigView) GetStringSlice(key string) []string {
+	return v.cm.GetStringSlice(key)
+}
+
+func (v ConfigView) GetIntSlice(key string) []int {
+	return v.cm.GetIntSlice(key)
+}
+
+func (v ConfigView) GetStringMap(key string) map[string]any {
+	return v.cm.GetStringMap(key)
+}
+
+func (v ConfigView) GetStringMapString(key string) map[string]string {
+	return v.cm.GetStringMapString(key)
+}
+
+func (v ConfigView) UnmarshalKey(key string, out any) error {
+	return v.cm.UnmarshalKey(key, out)
+}
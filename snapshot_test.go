@@ -0,0 +1,34 @@
+package config
+
+import "testing"
+
+func TestSnapshotFixesBoundEnv(t *testing.T) {
+	t.Setenv("JETY_TEST_DB_URL", "before")
+
+	c := NewConfigManager()
+	if err := c.SetDefault("db", map[string]any{"url": "default", "pool": 5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.BindEnv("db.url", "JETY_TEST_DB_URL"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.BindEnv("token", "JETY_TEST_TOKEN"); err != nil {
+		t.Fatal(err)
+	}
+	view := c.Snapshot()
+	t.Setenv("JETY_TEST_DB_URL", "after")
+	t.Setenv("JETY_TEST_TOKEN", "after")
+
+	if got := view.GetString("db.url"); got != "before" {
+		t.Errorf("view GetString(db.url) = %q, want before", got)
+	}
+	if got := view.GetStringMap("db")["url"]; got != "before" {
+		t.Errorf("view GetStringMap(db)[url] = %#v, want before", got)
+	}
+	if view.IsSet("token") {
+		t.Error("view IsSet(token) = true for a var set after Snapshot")
+	}
+	if got := c.GetString("db.url"); got != "after" {
+		t.Errorf("GetString(db.url) = %q, want after", got)
+	}
+}
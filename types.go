@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RegisterType declares the kind a top-level key must hold. The value is
+// converted once whenever the config is collapsed or set, and a value that
+// cannot be converted fails the load or the setter.
+func (c *ConfigManager) RegisterType(key string, kind reflect.Kind) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.frozen {
+		return
+	}
+	if c.types == nil {
+		c.types = make(map[string]reflect.Kind)
+	}
+	c.types[strings.ToLower(key)] = kind
+}
+
+// coerceTypes converts registered keys in combinedConfig. The caller must
+// hold the write lock.
+func (c *ConfigManager) coerceTypes() error {
+	for k, kind := range c.types {
+		v, ok := c.combinedConfig[k]
+		if !ok || v.Value == nil {
+			continue
+		}
+		val, err := coerce(v.Value, kind, c.intRounding)
+		if err != nil {
+			return fmt.Errorf("key %s: %w", v.Key, err)
+		}
+		c.combinedConfig[k] = ConfigMap{Key: v.Key, Value: val}
+	}
+	return nil
+}
+
+func coerce(in any, kind reflect.Kind, rounding IntRounding) (any, error) {
+	switch kind {
+	case reflect.String:
+		if s, ok := in.(string); ok {
+			return s, nil
+		}
+		return fmt.Sprintf("%v", in), nil
+	case reflect.Bool:
+		return toBool(in)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt64(in, rounding)
+		if err != nil {
+			return nil, err
+		}
+		out := reflect.New(kindType(kind)).Elem()
+		if out.OverflowInt(i) {
+			return nil, fmt.Errorf("value %d overflows %s", i, kind)
+		}
+		out.SetInt(i)
+		return out.Interface(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := toUint64(in, rounding)
+		if err != nil {
+			return nil, err
+		}
+		out := reflect.New(kindType(kind)).Elem()
+		if out.OverflowUint(u) {
+			return nil, fmt.Errorf("value %d overflows %s", u, kind)
+		}
+		out.SetUint(u)
+		return out.Interface(), nil
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat(in)
+		if err != nil {
+			return nil, err
+		}
+		if kind == reflect.Float32 {
+			return float32(f), nil
+		}
+		return f, nil
+	default:
+		return nil, fmt.Errorf("unsupported kind %s", kind)
+	}
+}
+
+// envOverDefault converts an env or command-line value overriding def to
+// the type of def and keeps its key, so that writing the config does not
+// turn typed values into strings. Values that do not convert are kept as
+// they are. The caller must hold the lock.
+func (c *ConfigManager) envOverDefault(def, env ConfigMap) ConfigMap {
+	out := ConfigMap{Key: def.Key, Value: env.Value}
+	if def.Value == nil || env.Value == nil {
+		return out
+	}
+	t := reflect.TypeOf(def.Value)
+	if t == durationType {
+		// a bare number is a count of the duration unit, not nanoseconds
+		d, err := toDuration(env.Value, c.keyUnit(strings.ToLower(def.Key)))
+		if err == nil {
+			out.Value = d
+		}
+		return out
+	}
+	var val any
+	var err error
+	switch t.Kind() {
+	case reflect.Bool:
+		val, err = toBool(env.Value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		val, err = coerce(env.Value, t.Kind(), c.intRounding)
+	default:
+		return out
+	}
+	if err != nil {
+		return out
+	}
+	out.Value = reflect.ValueOf(val).Convert(t).Interface()
+	return out
+}
+
+func kindType(kind reflect.Kind) reflect.Type {
+	switch kind {
+	case reflect.Int:
+		return reflect.TypeOf(int(0))
+	case reflect.Int8:
+		return reflect.TypeOf(int8(0))
+	case reflect.Int16:
+		return reflect.TypeOf(int16(0))
+	case reflect.Int32:
+		return reflect.TypeOf(int32(0))
+	case reflect.Int64:
+		return reflect.TypeOf(int64(0))
+	case reflect.Uint:
+		return reflect.TypeOf(uint(0))
+	case reflect.Uint8:
+		return reflect.TypeOf(uint8(0))
+	case reflect.Uint16:
+		return reflect.TypeOf(uint16(0))
+	case reflect.Uint32:
+		return reflect.TypeOf(uint32(0))
+	default:
+		return reflect.TypeOf(uint64(0))
+	}
+}
+
+// checkSerializable reports values that none of the config encoders can
+// write, so that they are rejected when set rather than when written.
+func checkSerializable(v any) error {
+	return checkSerializableValue(reflect.ValueOf(v), "", map[uintptr]bool{})
+}
+
+func checkSerializableValue(rv reflect.Value, path string, seen map[uintptr]bool) error {
+	if !rv.IsValid() {
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		if path == "" {
+			return fmt.Errorf("%w: %s", ErrUnsupportedType, rv.Type())
+		}
+		return fmt.Errorf("%w: %s at %s", ErrUnsupportedType, rv.Type(), path)
+	case reflect.Pointer:
+		if rv.IsNil() || seen[rv.Pointer()] {
+			return nil
+		}
+		seen[rv.Pointer()] = true
+		return checkSerializableValue(rv.Elem(), path, seen)
+	case reflect.Interface:
+		return checkSerializableValue(rv.Elem(), path, seen)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := checkSerializableValue(rv.Index(i), fmt.Sprintf("%s[%d]", path, i), seen); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := rv.MapRange()
+		for iter.Next() {
+			if err := checkSerializableValue(iter.Value(), joinKey(path, fmt.Sprintf("%v", iter.Key().Interface())), seen); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if err := checkSerializableValue(rv.Field(i), joinKey(path, t.Field(i).Name), seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDefaultIntSurvivesWrite(t *testing.T) {
+	t.Setenv("RETRIES", "5")
+
+	c := NewConfigManager()
+	if err := c.SetDefault("retries", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetDefault("name", "jety"); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := c.WriteConfigTo(&buf, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]any
+	dec := json.NewDecoder(&buf)
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := out["retries"].(json.Number); !ok || got.String() != "5" {
+		t.Errorf("retries = %#v, want the integer 5", out["retries"])
+	}
+	if got := out["name"]; got != "jety" {
+		t.Errorf("name = %#v, want jety", got)
+	}
+}
+
+func TestDurationDefaultFromEnvAndArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		args []string
+		want time.Duration
+	}{
+		{"env bare number", "30", nil, 30 * time.Second},
+		{"env fractional", "1.5", nil, 1500 * time.Millisecond},
+		{"env with unit", "2m", nil, 2 * time.Minute},
+		{"arg bare number", "", []string{"--timeout=45"}, 45 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.env != "" {
+				t.Setenv("TIMEOUT", tt.env)
+			}
+			c := NewConfigManager()
+			c.SetDurationUnit(time.Second)
+			if err := c.BindArgs(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.SetDefault("timeout", time.Second); err != nil {
+				t.Fatal(err)
+			}
+			if got := c.GetDuration("timeout"); got != tt.want {
+				t.Errorf("GetDuration(timeout) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisteredTypeOnSet(t *testing.T) {
+	c := NewConfigManager()
+	c.RegisterType("port", reflect.Int)
+	if err := c.Set("port", "8080"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Get("port"); got != 8080 {
+		t.Errorf("Get(port) = %#v, want int 8080", got)
+	}
+	if err := c.Set("port", "abc"); !errors.Is(err, ErrWrongType) {
+		t.Errorf("Set(port, abc) error = %v, want ErrWrongType", err)
+	}
+	if got := c.Get("port"); got != 8080 {
+		t.Errorf("after a failed Set, Get(port) = %#v, want int 8080", got)
+	}
+	c.RegisterType("workers", reflect.Int)
+	if err := c.SetDefault("workers", "many"); !errors.Is(err, ErrWrongType) {
+		t.Errorf("SetDefault(workers, many) error = %v, want ErrWrongType", err)
+	}
+	if c.IsSet("workers") {
+		t.Error("IsSet(workers) = true after a failed SetDefault")
+	}
+}
+
+func TestRegisteredUint64AboveMaxInt64(t *testing.T) {
+	c := NewConfigManager()
+	c.RegisterType("id", reflect.Uint64)
+	if err := c.Set("id", "18446744073709551615"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Get("id"); got != uint64(math.MaxUint64) {
+		t.Errorf("Get(id) = %#v, want uint64 %d", got, uint64(math.MaxUint64))
+	}
+	if err := c.Set("id", -1); err == nil {
+		t.Error("Set(id, -1) = nil, want an error")
+	}
+}
+
+func TestBoolDefaultFromEnvMatchesGetBool(t *testing.T) {
+	for _, env := range []string{"2", "-1", "0", "TRUE", "t", "no"} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv("VERBOSE", env)
+			c := NewConfigManager()
+			if err := c.SetDefault("verbose", false); err != nil {
+				t.Fatal(err)
+			}
+			want, err := toBool(env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.Get("verbose"); got != want {
+				t.Errorf("Get(verbose) = %#v, want %v", got, want)
+			}
+		})
+	}
+}
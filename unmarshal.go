@@ -0,0 +1,372 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrUnknownKeys = errors.New("unknown config keys")
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+type decoder struct {
+	exact        bool
+	durationUnit time.Duration
+	keyUnits     map[string]time.Duration
+	intRounding  IntRounding
+	unknown      []string
+	used         func(string)
+}
+
+// Unmarshal decodes the merged config into out, which must be a non-nil
+// pointer to a struct. Fields are matched by their jety, mapstructure,
+// json, yaml or toml tag, or by name, ignoring case. Values are converted
+// as by the typed getters, and keys without a matching field are ignored.
+func (c *ConfigManager) Unmarshal(out any) error {
+	return c.unmarshal(out, false)
+}
+
+// UnmarshalExact is like Unmarshal but fails with ErrUnknownKeys when the
+// config has keys that no field consumes.
+func (c *ConfigManager) UnmarshalExact(out any) error {
+	return c.unmarshal(out, true)
+}
+
+func (c *ConfigManager) unmarshal(out any, exact bool) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("unmarshal target must be a non-nil pointer to a struct, got %T", out)
+	}
+	c.ensureCollapsed()
+	c.mutex.RLock()
+	settings := make(map[string]any, len(c.combinedConfig))
+	for k, v := range c.combinedConfig {
+		settings[k] = v.Value
+	}
+	c.mutex.RUnlock()
+	nestDottedKeys(settings)
+
+	d := c.newDecoder(exact)
+	if err := d.decode("", settings, rv.Elem()); err != nil {
+		return err
+	}
+	if len(d.unknown) > 0 {
+		sort.Strings(d.unknown)
+		return fmt.Errorf("%w: %s", ErrUnknownKeys, strings.Join(d.unknown, ", "))
+	}
+	return nil
+}
+
+// nestDottedKeys moves flat keys such as "db.host" into the map at their
+// root key, matched ignoring case, so they decode into nested structs and
+// are written as nested tables. Keys whose root holds a non-map value are
+// left as they are.
+func nestDottedKeys(settings map[string]any) {
+	keys := make([]string, 0, len(settings))
+	for k := range settings {
+		if strings.Contains(k, ".") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts := strings.Split(k, ".")
+		root := parts[0]
+		for existing := range settings {
+			if !strings.Contains(existing, ".") && strings.EqualFold(existing, root) {
+				root = existing
+				break
+			}
+		}
+		if base, ok := settings[root]; ok && base != nil {
+			if _, ok := toStringMap(base); !ok {
+				continue
+			}
+		}
+		settings[root] = setNested(settings[root], parts[1:], settings[k])
+		delete(settings, k)
+	}
+}
+
+// UnmarshalKey decodes the value at key into out, which must be a non-nil
+// pointer. Maps of structs, such as map[string]ServerConfig, decode each
+// entry into the element type.
+func (c *ConfigManager) UnmarshalKey(key string, out any) error {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("unmarshal target must be a non-nil pointer, got %T", out)
+	}
+	v, ok := c.lookup(key)
+	if !ok {
+		return nil
+	}
+	d := c.newDecoder(false)
+	return d.decode(key, v.Value, rv.Elem())
+}
+
+// GetObject decodes the value at key into a new T.
+func GetObject[T any](c *ConfigManager, key string) (T, error) {
+	var out T
+	if _, ok := c.lookup(key); !ok {
+		return out, fmt.Errorf("%w: %s", ErrKeyNotFound, key)
+	}
+	if err := c.UnmarshalKey(key, &out); err != nil {
+		var zero T
+		return zero, err
+	}
+	return out, nil
+}
+
+func (c *ConfigManager) newDecoder(exact bool) *decoder {
+	c.mutex.RLock()
+	keyUnits := make(map[string]time.Duration, len(c.keyDurationUnits))
+	for k, v := range c.keyDurationUnits {
+		keyUnits[k] = v
+	}
+	trackUsage := c.trackUsage
+	c.mutex.RUnlock()
+	d := &decoder{exact: exact, durationUnit: c.getDurationUnit(), keyUnits: keyUnits, intRounding: c.getIntRounding()}
+	if trackUsage {
+		d.used = c.markUsed
+	}
+	return d
+}
+
+func fieldName(f reflect.StructField) (string, bool) {
+	for _, tag := range []string{"jety", "mapstructure", "json", "yaml", "toml"} {
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, true
+		}
+	}
+	return f.Name, true
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func (d *decoder) decode(path string, in any, out reflect.Value) error {
+	if d.used != nil && !strings.Contains(path, "[") {
+		// structs only consume the keys that match their fields, which
+		// are marked as they are decoded
+		t := out.Type()
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t == timeType {
+			d.used(strings.ToLower(path))
+		}
+	}
+	if in == nil {
+		return nil
+	}
+	if out.Type() == durationType {
+		unit := d.durationUnit
+		if u, ok := d.keyUnits[strings.ToLower(path)]; ok && u > 0 {
+			unit = u
+		}
+		dur, err := toDuration(in, unit)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		out.SetInt(int64(dur))
+		return nil
+	}
+	switch out.Kind() {
+	case reflect.Pointer:
+		if out.IsNil() {
+			out.Set(reflect.New(out.Type().Elem()))
+		}
+		return d.decode(path, in, out.Elem())
+	case reflect.Interface:
+		out.Set(reflect.ValueOf(in))
+		return nil
+	case reflect.Struct:
+		return d.decodeStruct(path, in, out)
+	case reflect.Map:
+		return d.decodeMap(path, in, out)
+	case reflect.Slice:
+		return d.decodeSlice(path, in, out)
+	case reflect.String:
+		switch val := in.(type) {
+		case string:
+			out.SetString(val)
+		default:
+			out.SetString(fmt.Sprintf("%v", val))
+		}
+		return nil
+	case reflect.Bool:
+		b, err := toBool(in)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		out.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt64(in, d.intRounding)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if out.OverflowInt(i) {
+			return fmt.Errorf("%s: value %d overflows %s", path, i, out.Type())
+		}
+		out.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := toUint64(in, d.intRounding)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if out.OverflowUint(u) {
+			return fmt.Errorf("%s: value %d overflows %s", path, u, out.Type())
+		}
+		out.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat(in)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		out.SetFloat(f)
+		return nil
+	default:
+		return fmt.Errorf("%s: unsupported field type %s", path, out.Type())
+	}
+}
+
+func (d *decoder) decodeStruct(path string, in any, out reflect.Value) error {
+	m, ok := toStringMap(in)
+	if !ok {
+		return fmt.Errorf("%s: cannot decode %T into %s", path, in, out.Type())
+	}
+	lowered := make(map[string]string, len(m))
+	for k := range m {
+		lowered[strings.ToLower(k)] = k
+	}
+	used := make(map[string]bool, len(m))
+	if err := d.decodeFields(path, m, lowered, used, out); err != nil {
+		return err
+	}
+	if d.exact {
+		for k := range m {
+			if !used[k] {
+				d.unknown = append(d.unknown, joinKey(path, k))
+			}
+		}
+	}
+	return nil
+}
+
+func (d *decoder) decodeFields(path string, m map[string]any, lowered map[string]string, used map[string]bool, out reflect.Value) error {
+	t := out.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if embedded(f) {
+			fv := out.Field(i)
+			if fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					if !fv.CanSet() {
+						continue
+					}
+					fv.Set(reflect.New(f.Type.Elem()))
+				}
+				fv = fv.Elem()
+			}
+			if err := d.decodeFields(path, m, lowered, used, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		name, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+		key, ok := lowered[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+		used[key] = true
+		if err := d.decode(joinKey(path, key), m[key], out.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// embedded reports whether f is an anonymous struct field without a tag
+// name, or one tagged squash, whose fields are promoted to the parent's
+// keys.
+func embedded(f reflect.StructField) bool {
+	t := f.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+	for _, tag := range []string{"jety", "mapstructure"} {
+		_, opts, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if opts == "squash" {
+			return true
+		}
+	}
+	if !f.Anonymous {
+		return false
+	}
+	name, ok := fieldName(f)
+	return ok && name == f.Name
+}
+
+func (d *decoder) decodeMap(path string, in any, out reflect.Value) error {
+	m, ok := toStringMap(in)
+	if !ok {
+		return fmt.Errorf("%s: cannot decode %T into %s", path, in, out.Type())
+	}
+	t := out.Type()
+	if t.Key().Kind() != reflect.String {
+		return fmt.Errorf("%s: unsupported map key type %s", path, t.Key())
+	}
+	if out.IsNil() {
+		out.Set(reflect.MakeMapWithSize(t, len(m)))
+	}
+	for k, v := range m {
+		elem := reflect.New(t.Elem()).Elem()
+		if err := d.decode(joinKey(path, k), v, elem); err != nil {
+			return err
+		}
+		out.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), elem)
+	}
+	return nil
+}
+
+func (d *decoder) decodeSlice(path string, in any, out reflect.Value) error {
+	rv := reflect.ValueOf(in)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return fmt.Errorf("%s: cannot decode %T into %s", path, in, out.Type())
+	}
+	s := reflect.MakeSlice(out.Type(), rv.Len(), rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		if err := d.decode(fmt.Sprintf("%s[%d]", path, i), rv.Index(i).Interface(), s.Index(i)); err != nil {
+			return err
+		}
+	}
+	out.Set(s)
+	return nil
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUnmarshalUint(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    uint8
+		wantErr bool
+	}{
+		{"int", `{"u": 200}`, 200, false},
+		{"string", `{"u": " 1_0 "}`, 10, false},
+		{"int overflow", `{"u": 300}`, 0, true},
+		{"string overflow", `{"u": "300"}`, 0, true},
+		{"negative", `{"u": -1}`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfigManager()
+			if err := c.SetConfigType("json"); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.ReadConfig(strings.NewReader(tt.data)); err != nil {
+				t.Fatal(err)
+			}
+			var out struct{ U uint8 }
+			err := c.Unmarshal(&out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if out.U != tt.want {
+				t.Errorf("U = %d, want %d", out.U, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalBoolMatchesGetBool(t *testing.T) {
+	for _, value := range []string{`true`, `"TRUE"`, `"2"`, `"0"`, `1`, `0`, `"no"`} {
+		t.Run(value, func(t *testing.T) {
+			c := NewConfigManager()
+			if err := c.SetConfigType("json"); err != nil {
+				t.Fatal(err)
+			}
+			if err := c.ReadConfig(strings.NewReader(`{"b": ` + value + `}`)); err != nil {
+				t.Fatal(err)
+			}
+			var out struct{ B bool }
+			if err := c.Unmarshal(&out); err != nil {
+				t.Fatal(err)
+			}
+			if want := c.GetBool("b"); out.B != want {
+				t.Errorf("B = %v, want %v as from GetBool", out.B, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalNestedStruct(t *testing.T) {
+	type Database struct {
+		Host    string        `json:"host"`
+		Port    int           `mapstructure:"port"`
+		Timeout time.Duration `yaml:"timeout"`
+	}
+	type Config struct {
+		Name     string
+		Database Database `json:"database"`
+		Tags     []string `json:"tags"`
+		Retry    time.Duration
+	}
+	t.Setenv("APP_NAME", "from-env")
+
+	c := NewConfigManager()
+	c.SetEnvPrefix("APP")
+	c.SetDurationUnit(time.Second)
+	if err := c.SetConfigType("yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetDefault("name", "default"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetDefault("retry", "1m"); err != nil {
+		t.Fatal(err)
+	}
+	data := `
+database:
+  host: db.local
+  port: "5432"
+  timeout: 5s
+tags: [a, b, 3]
+`
+	if err := c.ReadConfig(strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("database.timeout", 30); err != nil {
+		t.Fatal(err)
+	}
+	var got Config
+	if err := c.Unmarshal(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := Config{
+		Name:     "from-env",
+		Database: Database{Host: "db.local", Port: 5432, Timeout: 30 * time.Second},
+		Tags:     []string{"a", "b", "3"},
+		Retry:    time.Minute,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, want)
+	}
+	var notStruct int
+	if err := c.Unmarshal(&notStruct); err == nil {
+		t.Error("Unmarshal(*int) = nil, want an error")
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// TrackUsage records which keys are read by getters and Unmarshal so that
+// UnusedKeys can report config that is never consumed. Tracking is off by
+// default; disabling it discards the recorded keys.
+func (c *ConfigManager) TrackUsage(enable bool) {
+	c.mutex.Lock()
+	c.trackUsage = enable
+	c.mutex.Unlock()
+	c.usageMutex.Lock()
+	defer c.usageMutex.Unlock()
+	if !enable {
+		c.usedKeys = nil
+	}
+}
+
+func (c *ConfigManager) markUsed(lower string) {
+	c.usageMutex.Lock()
+	defer c.usageMutex.Unlock()
+	if c.usedKeys == nil {
+		c.usedKeys = make(map[string]bool)
+	}
+	c.usedKeys[lower] = true
+}
+
+// UnusedKeys returns the sorted, dotted paths of config leaves that have not
+// been read since usage tracking was enabled. Reading a map marks every key
+// beneath it as used.
+func (c *ConfigManager) UnusedKeys() []string {
+	c.ensureCollapsed()
+	c.mutex.RLock()
+	settings := make(map[string]any, len(c.combinedConfig))
+	for k, v := range c.combinedConfig {
+		settings[k] = v.Value
+	}
+	c.mutex.RUnlock()
+	flat := make(map[string]any)
+	flattenInto(flat, "", settings)
+
+	c.usageMutex.Lock()
+	defer c.usageMutex.Unlock()
+	var unused []string
+	for k := range flat {
+		lower := strings.ToLower(k)
+		if !c.isUsed(lower) {
+			unused = append(unused, lower)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// isUsed reports whether lower or one of its parents was read. The caller
+// must hold usageMutex.
+func (c *ConfigManager) isUsed(lower string) bool {
+	for {
+		if c.usedKeys[lower] {
+			return true
+		}
+		i := strings.LastIndex(lower, ".")
+		if i < 0 {
+			return false
+		}
+		lower = lower[:i]
+	}
+}
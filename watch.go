@@ -0,0 +1,81 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type fileState struct {
+	target  string
+	modTime time.Time
+	size    int64
+}
+
+func statConfigFile(filename string) (fileState, error) {
+	target, err := filepath.EvalSymlinks(filename)
+	if err != nil {
+		return fileState{}, err
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return fileState{}, err
+	}
+	return fileState{target: target, modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// WatchConfig polls the config file every interval until ctx is done and
+// rereads it whenever it changes. Symlinks are resolved on every poll, so
+// swapping a symlink to a new target, as Kubernetes does for mounted
+// ConfigMaps, triggers a reload. onChange, if not nil, receives the result
+// of each reload.
+func (c *ConfigManager) WatchConfig(ctx context.Context, interval time.Duration, onChange func(error)) error {
+	c.mutex.RLock()
+	filename := c.configFileUsed
+	c.mutex.RUnlock()
+	if filename == "" {
+		return ErrNoConfigFile
+	}
+	last, err := statConfigFile(filename)
+	if err != nil {
+		return err
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			state, err := statConfigFile(filename)
+			if err != nil || state == last {
+				// the target may be missing briefly while it is swapped
+				continue
+			}
+			last = state
+			err = c.ReadInConfig()
+			if onChange != nil {
+				onChange(err)
+			}
+		}
+	}()
+	return nil
+}
+
+// Reload rereads the config file and reports whether the effective config
+// differs from before the reload.
+func (c *ConfigManager) Reload() (bool, error) {
+	c.ensureCollapsed()
+	c.mutex.RLock()
+	before := cloneConfig(c.combinedConfig)
+	c.mutex.RUnlock()
+	if err := c.ReadInConfig(); err != nil {
+		return false, err
+	}
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return len(diffConfig(before, c.combinedConfig)) > 0, nil
+}